@@ -0,0 +1,47 @@
+package client
+
+import (
+	"github.com/jessevdk/go-flags"
+)
+
+type Cancel struct {
+	Args struct {
+		Id string `positional-arg-name:"id" required:"true" description:"id of the wine label"`
+	} `positional-args:"true"`
+	Url     string `long:"url" description:"Specify URL of REST API"`
+	Keyfile string `long:"keyfile" description:"Identify file containing user's private key"`
+	Wait    uint   `long:"wait" description:"Set time, in seconds, to wait for transaction to commit"`
+}
+
+func (args *Cancel) Name() string {
+	return "cancel"
+}
+
+func (args *Cancel) KeyfilePassed() string {
+	return args.Keyfile
+}
+
+func (args *Cancel) UrlPassed() string {
+	return args.Url
+}
+
+func (args *Cancel) Register(parent *flags.Command) error {
+	_, err := parent.AddCommand(args.Name(), "Cancels a wine label transfer", "Sends a transaction withdrawing or declining the open offer of the wine label <id>.", args)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (args *Cancel) Run() error {
+	// Construct client
+	id := args.Args.Id
+	wait := args.Wait
+
+	WineLabelClient, err := GetClient(args, true)
+	if err != nil {
+		return err
+	}
+	_, err = WineLabelClient.CancelTransfer(id, wait)
+	return err
+}
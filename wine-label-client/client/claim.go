@@ -0,0 +1,49 @@
+package client
+
+import (
+	"github.com/jessevdk/go-flags"
+)
+
+type Claim struct {
+	Args struct {
+		Id     string `positional-arg-name:"id" required:"true" description:"id of the wine label"`
+		Secret string `positional-arg-name:"secret" required:"true" description:"secret under the label's scratch-off"`
+	} `positional-args:"true"`
+	Url     string `long:"url" description:"Specify URL of REST API"`
+	Keyfile string `long:"keyfile" description:"Identify file containing user's private key"`
+	Wait    uint   `long:"wait" description:"Set time, in seconds, to wait for transaction to commit"`
+}
+
+func (args *Claim) Name() string {
+	return "claim"
+}
+
+func (args *Claim) KeyfilePassed() string {
+	return args.Keyfile
+}
+
+func (args *Claim) UrlPassed() string {
+	return args.Url
+}
+
+func (args *Claim) Register(parent *flags.Command) error {
+	_, err := parent.AddCommand(args.Name(), "Claims a wine label", "Sends a transaction revealing the <secret> under the scratch-off of the wine label <id>, claiming it. A label can only be claimed once.", args)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (args *Claim) Run() error {
+	// Construct client
+	id := args.Args.Id
+	secret := args.Args.Secret
+	wait := args.Wait
+
+	WineLabelClient, err := GetClient(args, true)
+	if err != nil {
+		return err
+	}
+	_, err = WineLabelClient.Claim(id, secret, wait)
+	return err
+}
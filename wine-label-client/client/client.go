@@ -2,7 +2,6 @@ package client
 
 import (
 	bytes2 "bytes"
-	"crypto/sha512"
 	"encoding/base64"
 	"encoding/hex"
 	"errors"
@@ -12,22 +11,23 @@ import (
 	"net/http"
 	"os/user"
 	"path"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
-	cbor "github.com/brianolson/cbor_go"
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/sawtooth-sdk-go/protobuf/batch_pb2"
 	"github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
 	"github.com/hyperledger/sawtooth-sdk-go/signing"
+	"github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v2"
+
+	"wine-label-protocol/protocol"
 )
 
 const (
 	// String literals
-	FAMILY_NAME       string = "wine-label"
-	FAMILY_VERSION    string = "1.0"
 	DISTRIBUTION_NAME string = "sawtooth-intkey"
 	DEFAULT_URL       string = "http://127.0.0.1:8008"
 
@@ -37,26 +37,15 @@ const (
 	STATE_API        string = "state"
 	// Content types
 	CONTENT_TYPE_OCTET_STREAM string = "application/octet-stream"
-	// Integer literals
-	FAMILY_NAMESPACE_ADDRESS_LENGTH uint = 6
-	FAMILY_VERB_ADDRESS_LENGTH      uint = 64
 )
 
 type WineLabelClient struct {
-	url    string
-	signer *signing.Signer
-}
-
-type WineLabelPayload struct {
-	Payload
-	Verb string
-}
-
-type Payload struct {
-	WineLabelID string
-	PrintedAt   string
-	Longitude   string
-	Lattitude   string
+	url           string
+	signer        *signing.Signer
+	familyName    string
+	namespace     protocol.Namespace
+	familyVersion string
+	encoding      protocol.Encoding
 }
 
 func NewWineLabelClient(url string, keyfile string) (WineLabelClient, error) {
@@ -75,88 +64,558 @@ func NewWineLabelClient(url string, keyfile string) (WineLabelClient, error) {
 	}
 	cryptoFactory := signing.NewCryptoFactory(signing.NewSecp256k1Context())
 	signer := cryptoFactory.NewSigner(privateKey)
-	return WineLabelClient{url, signer}, nil
+	namespace := protocol.NewNamespace(protocol.FAMILY_NAME)
+	return WineLabelClient{url, signer, protocol.FAMILY_NAME, namespace, protocol.FAMILY_VERSION, protocol.ENCODING_CBOR}, nil
+}
+
+// WithTenant returns a copy of the client that reads and writes the labels
+// of a consortium's tenant rather than of the default family.
+func (self WineLabelClient) WithTenant(tenant string) (WineLabelClient, error) {
+	familyName, err := protocol.TenantFamilyName(tenant)
+	if err != nil {
+		return self, err
+	}
+	self.familyName = familyName
+	self.namespace = protocol.NewNamespace(familyName)
+	return self, nil
+}
+
+// WithFamilyVersion returns a copy of the client that sends transactions
+// as the given version of the wine-label family.
+func (self WineLabelClient) WithFamilyVersion(version string) (WineLabelClient, error) {
+	for _, known := range protocol.FAMILY_VERSIONS {
+		if version == known {
+			self.familyVersion = version
+			return self, nil
+		}
+	}
+	return self, errors.New(fmt.Sprintf("Unsupported family version: %v", version))
+}
+
+// WithEncoding returns a copy of the client that encodes payloads in the
+// given encoding. Protobuf payloads need family version 2.0.
+func (self WineLabelClient) WithEncoding(encoding protocol.Encoding) (WineLabelClient, error) {
+	for _, known := range protocol.ENCODINGS {
+		if encoding == known {
+			self.encoding = encoding
+			return self, nil
+		}
+	}
+	return self, errors.New(fmt.Sprintf("Unsupported encoding: %v", encoding))
 }
 
+// Set creates a label, or corrects one that is still only printed. The
+// label must carry its coordinates and print event, and may name a lot.
+// secret, if not empty, commits the label to the secret printed under its
+// scratch-off.
 func (self WineLabelClient) Set(
-	labelID, location, long, lat string, wait uint) (string, error) {
-	return self.sendTransaction("set", labelID, location, long, lat, wait)
+	label protocol.Payload, secret string, wait uint) (string, error) {
+	if _, err := protocol.ParseGeoPoint(label.Lattitude, label.Longitude); err != nil {
+		return "", err
+	}
+	if err := checkPrint(label.Print); err != nil {
+		return "", err
+	}
+	if secret != "" {
+		var err error
+		label.Secret, err = protocol.NewLabelSecret(secret)
+		if err != nil {
+			return "", err
+		}
+	}
+	payload := protocol.WineLabelPayload{Payload: label, Verb: protocol.VERB_SET}
+	return self.sendTransaction(payload, wait)
 }
 
 func (self WineLabelClient) Delete(
 	labelID string, wait uint) (string, error) {
-	return self.sendTransaction("delete", "", "", "", "", wait)
+	payload := protocol.WineLabelPayload{Verb: protocol.VERB_DELETE}
+	payload.WineLabelID = labelID
+	return self.sendTransaction(payload, wait)
+}
+
+// Transition moves a label along its lifecycle using one of the lifecycle
+// verbs, e.g. protocol.VERB_BOTTLE.
+func (self WineLabelClient) Transition(
+	labelID, verb string, wait uint) (string, error) {
+	if _, ok := protocol.StatusForVerb(verb); !ok {
+		return "", errors.New(fmt.Sprintf("Not a lifecycle verb: %v", verb))
+	}
+	payload := protocol.WineLabelPayload{Verb: verb}
+	payload.WineLabelID = labelID
+	return self.sendTransaction(payload, wait)
+}
+
+// Transfer offers custody of a label to the holder of the recipient public
+// key. Ownership only moves once the recipient calls Accept.
+func (self WineLabelClient) Transfer(
+	labelID, recipient string, wait uint) (string, error) {
+	payload := protocol.WineLabelPayload{Verb: protocol.VERB_OFFER, Recipient: recipient}
+	payload.WineLabelID = labelID
+	return self.sendTransaction(payload, wait)
+}
+
+// Accept takes custody of a label that was offered to this client's key.
+func (self WineLabelClient) Accept(
+	labelID string, wait uint) (string, error) {
+	payload := protocol.WineLabelPayload{Verb: protocol.VERB_ACCEPT}
+	payload.WineLabelID = labelID
+	return self.sendTransaction(payload, wait)
+}
+
+// CancelTransfer withdraws, or declines, an open custody offer.
+func (self WineLabelClient) CancelTransfer(
+	labelID string, wait uint) (string, error) {
+	payload := protocol.WineLabelPayload{Verb: protocol.VERB_CANCEL}
+	payload.WineLabelID = labelID
+	return self.sendTransaction(payload, wait)
+}
+
+// Scan records that this client's key scanned a label at a location now.
+// Anyone may scan a label; scans that imply the label was cloned flag it as
+// suspicious.
+func (self WineLabelClient) Scan(
+	labelID, location, long, lat string, wait uint) (string, error) {
+	if _, err := protocol.ParseGeoPoint(lat, long); err != nil {
+		return "", err
+	}
+	payload := protocol.WineLabelPayload{Verb: protocol.VERB_SCAN}
+	payload.WineLabelID = labelID
+	payload.PrintedAt = location
+	payload.Longitude = long
+	payload.Lattitude = lat
+	return self.sendTransaction(payload, wait)
+}
+
+// Claim reveals the secret under a label's scratch-off, claiming the label
+// for this client's key. A label can only be claimed once; a wrong or
+// repeated claim is still committed, as a counterfeit signal, so when
+// waiting Claim reads the label back to tell whether it succeeded.
+func (self WineLabelClient) Claim(
+	labelID, secret string, wait uint) (string, error) {
+	payload := protocol.WineLabelPayload{Verb: protocol.VERB_CLAIM, RevealedSecret: secret}
+	payload.WineLabelID = labelID
+	response, err := self.sendTransaction(payload, wait)
+	if err != nil || wait == 0 {
+		return response, err
+	}
+	record, err := self.showRecord(labelID)
+	if err != nil {
+		return response, err
+	}
+	if record.Claim == nil || record.Claim.Claimant != self.PublicKey() {
+		return response, errors.New(fmt.Sprintf("Claim of wine label %v was rejected", labelID))
+	}
+	return response, nil
+}
+
+// Recall recalls every bottle of a lot. The client's key must be
+// registered to the lot's producer or listed in the wine-label.regulators
+// setting.
+func (self WineLabelClient) Recall(
+	lotID, reason string, wait uint) (string, error) {
+	if reason == "" {
+		return "", errors.New("A recall must give a reason")
+	}
+	payload := protocol.WineLabelPayload{Verb: protocol.VERB_RECALL, Reason: reason}
+	payload.LotDetails.LotID = lotID
+	return self.sendTransaction(payload, wait)
+}
+
+// RegisterOrganisation adds a winery, printer, distributor or retailer to
+// the registry. The client's key must be listed in the wine-label.admins
+// setting.
+func (self WineLabelClient) RegisterOrganisation(
+	org protocol.OrgPayload, wait uint) (string, error) {
+	payload := protocol.WineLabelPayload{Verb: protocol.VERB_REGISTER_ORG, Organisation: org}
+	return self.sendTransaction(payload, wait)
+}
+
+// Mint creates a roll of labels in one transaction, all owned by the
+// client's key and sharing the print metadata in mint.
+func (self WineLabelClient) Mint(
+	mint protocol.MintPayload, wait uint) (string, error) {
+	if _, err := mint.Labels(); err != nil {
+		return "", err
+	}
+	if err := checkPrint(mint.Print); err != nil {
+		return "", err
+	}
+	payload := protocol.WineLabelPayload{Verb: protocol.VERB_MINT, Mint: mint}
+	return self.sendTransaction(payload, wait)
 }
 
-func (self WineLabelClient) List() ([]WineLabelPayload, error) {
+// checkPrint validates a print event, and checks its time against this
+// client's clock since validators do not.
+func checkPrint(event protocol.PrintEvent) error {
+	if err := event.Check(); err != nil {
+		return err
+	}
+	return event.CheckClock(time.Now(), protocol.DEFAULT_MAX_CLOCK_SKEW)
+}
+
+// CreateLot declares a lot of wine for the winery the client's key is
+// registered to.
+func (self WineLabelClient) CreateLot(
+	lot protocol.LotPayload, wait uint) (string, error) {
+	payload := protocol.WineLabelPayload{Verb: protocol.VERB_CREATE_LOT, LotDetails: lot}
+	return self.sendTransaction(payload, wait)
+}
+
+// UpdateOrganisation replaces the name and key list of a registered
+// organisation. It must be signed by an admin or one of the organisation's
+// current keys.
+func (self WineLabelClient) UpdateOrganisation(
+	org protocol.OrgPayload, wait uint) (string, error) {
+	payload := protocol.WineLabelPayload{Verb: protocol.VERB_UPDATE_ORG, Organisation: org}
+	return self.sendTransaction(payload, wait)
+}
+
+// PublicKey returns the hex encoded public key the client signs with.
+func (self WineLabelClient) PublicKey() string {
+	return self.signer.GetPublicKey().AsHex()
+}
+
+func (self WineLabelClient) List() ([]Label, error) {
+	var toReturn []protocol.LabelRecord
+	entries, err := self.listState(self.namespace.SpacePrefix(protocol.LABEL_SPACE))
+	if err != nil {
+		return nil, err
+	}
+	listed := make(map[string]bool)
+	for _, entry := range entries {
+		foundMap, err := protocol.DecodeLabelRecord(entry.Data)
+		if err != nil {
+			return nil,
+				errors.New(fmt.Sprintf("Error binary decoding: %v", err))
+		}
+		if foundMap.WineLabelID == "" {
+			// A deleted label left behind by the original processor.
+			continue
+		}
+		listed[foundMap.WineLabelID] = true
+		toReturn = append(toReturn, foundMap)
+	}
+	legacy, err := self.listLegacy()
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range legacy {
+		if !listed[record.WineLabelID] {
+			listed[record.WineLabelID] = true
+			toReturn = append(toReturn, record)
+		}
+	}
+	return self.withRecalls(toReturn)
+}
+
+// listLegacy returns the labels still stored at the address the original
+// processor put them at. Those addresses do not follow the space layout and
+// may start with any space prefix, so the whole namespace is listed and an
+// entry is kept only when it decodes to a label whose LegacyLabelAddress it
+// sits at.
+func (self WineLabelClient) listLegacy() ([]protocol.LabelRecord, error) {
+	var toReturn []protocol.LabelRecord
+	entries, err := self.listState(string(self.namespace))
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		record, err := protocol.DecodeLabelRecord(entry.Data)
+		if err != nil || record.WineLabelID == "" {
+			continue
+		}
+		if entry.Address == self.namespace.LegacyLabelAddress(record.WineLabelID) {
+			toReturn = append(toReturn, record)
+		}
+	}
+	return toReturn, nil
+}
+
+// History returns the provenance of a label, oldest event first, after
+// checking that the events form an unbroken hash chain ending at the
+// label's recorded head.
+func (self WineLabelClient) History(labelID string) ([]protocol.HistoryEvent, error) {
+	head := ""
+	record, err := self.showRecord(labelID)
+	if err == nil {
+		head = record.HistoryHead
+	} else if _, withdrawn := err.(noSuchKey); !withdrawn {
+		return nil, err
+	}
+	entries, err := self.listState(self.namespace.HistoryPrefix(labelID))
+	if err != nil {
+		return nil, err
+	}
+	stored := make([][]byte, 0, len(entries))
+	for _, entry := range entries {
+		stored = append(stored, entry.Data)
+	}
+	return protocol.VerifyHistory(labelID, stored, head)
+}
+
+// noSuchKey is the error for state the REST API does not have.
+type noSuchKey string
+
+func (name noSuchKey) Error() string {
+	return fmt.Sprintf("No such key: %s", string(name))
+}
+
+type stateEntry struct {
+	Address string
+	Data    []byte
+}
+
+// listState fetches every state entry under an address prefix, sorted by
+// address.
+func (self WineLabelClient) listState(prefix string) ([]stateEntry, error) {
 	// API to call
-	var payload []WineLabelPayload
+	var entries []stateEntry
 	apiSuffix := fmt.Sprintf("%s?address=%s",
-		STATE_API, self.getPrefix())
+		STATE_API, prefix)
 	response, err := self.sendRequest(apiSuffix, []byte{}, "", "")
 	if err != nil {
-		return payload, err
+		return entries, err
 	}
-	var toReturn []WineLabelPayload
 	responseMap := make(map[interface{}]interface{})
 	err = yaml.Unmarshal([]byte(response), &responseMap)
 	if err != nil {
-		return payload,
+		return entries,
 			errors.New(fmt.Sprintf("Error reading response: %v", err))
 	}
-	encodedEntries := responseMap["data"].([]interface{})
+	encodedEntries, ok := responseMap["data"].([]interface{})
+	if !ok {
+		return entries, nil
+	}
 	for _, entry := range encodedEntries {
 		entryData, ok := entry.(map[interface{}]interface{})
 		if !ok {
-			return payload,
+			return entries,
 				errors.New("Error reading entry data")
 		}
+		address, _ := entryData["address"].(string)
 		stringData, ok := entryData["data"].(string)
 		if !ok {
-			return payload,
+			return entries,
 				errors.New("Error reading string data")
 		}
 		decodedBytes, err := base64.StdEncoding.DecodeString(stringData)
 		if err != nil {
-			return payload,
-				errors.New(fmt.Sprint("Error decoding: %v", err))
+			return entries,
+				errors.New(fmt.Sprintf("Error decoding: %v", err))
+		}
+		entries = append(entries, stateEntry{address, decodedBytes})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Address < entries[j].Address
+	})
+	return entries, nil
+}
+
+// Label is a wine label as the client reports it: the stored record and,
+// if the label's lot has been recalled, the recall.
+type Label struct {
+	protocol.LabelRecord
+	Recall *protocol.Recall
+}
+
+// Show returns a label, with the recall of its lot if there is one.
+func (self WineLabelClient) Show(labelID string) (Label, error) {
+	record, err := self.showRecord(labelID)
+	if err != nil {
+		return Label{}, err
+	}
+	labels, err := self.withRecalls([]protocol.LabelRecord{record})
+	if err != nil {
+		return Label{}, err
+	}
+	return labels[0], nil
+}
+
+// withRecalls looks up the recall status of the lots of records, reading
+// each lot once.
+func (self WineLabelClient) withRecalls(records []protocol.LabelRecord) ([]Label, error) {
+	recalls := make(map[string]*protocol.Recall)
+	labels := make([]Label, 0, len(records))
+	for _, record := range records {
+		recall, known := recalls[record.Lot]
+		if !known && record.Lot != "" {
+			lot, err := self.ShowLot(record.Lot)
+			if err != nil {
+				return nil, err
+			}
+			recall = lot.Recall
+			recalls[record.Lot] = recall
 		}
-		var foundMap WineLabelPayload
-		err = cbor.Loads(decodedBytes, &foundMap)
+		labels = append(labels, Label{LabelRecord: record, Recall: recall})
+	}
+	return labels, nil
+}
+
+// showRecord reads a label, falling back to the address the original
+// processor stored it at until the label next changes and is moved.
+func (self WineLabelClient) showRecord(labelID string) (protocol.LabelRecord, error) {
+	responseData, err := self.readState(self.namespace.LabelAddress(labelID), labelID)
+	if _, missing := err.(noSuchKey); missing {
+		legacyData, legacyErr := self.readState(self.namespace.LegacyLabelAddress(labelID), labelID)
+		if legacyErr == nil {
+			responseData, err = legacyData, nil
+		}
+	}
+	if err != nil {
+		return protocol.LabelRecord{}, err
+	}
+	responseFinal, err := protocol.DecodeLabelRecord(responseData)
+	if err != nil {
+		return protocol.LabelRecord{}, errors.New(fmt.Sprintf("Error binary decoding: %v", err))
+	}
+	if responseFinal.WineLabelID == "" {
+		// The original processor deleted labels by emptying them.
+		return protocol.LabelRecord{}, noSuchKey(labelID)
+	}
+	return responseFinal, nil
+}
+
+// ShowLot returns the record of a lot.
+func (self WineLabelClient) ShowLot(lotID string) (protocol.Lot, error) {
+	var lot protocol.Lot
+	data, err := self.readState(self.namespace.LotAddress(lotID), lotID)
+	if err != nil {
+		return lot, err
+	}
+	if err := protocol.DecodeRecord(data, &lot); err != nil {
+		return lot, errors.New(fmt.Sprintf("Error binary decoding: %v", err))
+	}
+	return lot, nil
+}
+
+// ListByOwner returns the labels owned by a public key.
+func (self WineLabelClient) ListByOwner(publicKey string) ([]Label, error) {
+	return self.listIndex(self.namespace.IndexPrefix(protocol.OWNER_INDEX_SPACE, publicKey))
+}
+
+// ListByLot returns every label attached to a lot.
+func (self WineLabelClient) ListByLot(lotID string) ([]Label, error) {
+	return self.listIndex(self.namespace.IndexPrefix(protocol.LOT_INDEX_SPACE, lotID))
+}
+
+// ListByFacility returns the labels minted at a facility.
+func (self WineLabelClient) ListByFacility(facility string) ([]Label, error) {
+	return self.listIndex(self.namespace.IndexPrefix(protocol.FACILITY_INDEX_SPACE, facility))
+}
+
+// ListByOrganisation returns the labels owned by any key of a registered
+// organisation, such as a winery.
+func (self WineLabelClient) ListByOrganisation(orgID string) ([]Label, error) {
+	var org protocol.Organisation
+	data, err := self.readState(self.namespace.OrganisationAddress(orgID), orgID)
+	if err != nil {
+		return nil, err
+	}
+	if err := protocol.DecodeRecord(data, &org); err != nil {
+		return nil, errors.New(fmt.Sprintf("Error binary decoding: %v", err))
+	}
+	var labels []Label
+	for _, key := range org.Keys {
+		owned, err := self.ListByOwner(key)
 		if err != nil {
-			return payload,
-				errors.New(fmt.Sprint("Error binary decoding: %v", err))
+			return nil, err
 		}
-		toReturn = append(toReturn, foundMap)
+		labels = append(labels, owned...)
 	}
-	return toReturn, nil
+	return labels, nil
+}
+
+// ListInBox returns the labels printed within a bounding box. The spatial
+// index narrows the search to the cells covering the box, and the labels
+// in those cells are then filtered by their exact position.
+func (self WineLabelClient) ListInBox(box protocol.BoundingBox) ([]Label, error) {
+	return self.listRegion(box, box.Contains)
+}
+
+// ListWithin returns the labels printed within km kilometres of center.
+func (self WineLabelClient) ListWithin(center protocol.GeoPoint, km float64) ([]Label, error) {
+	if km < 0 {
+		return nil, errors.New(fmt.Sprintf("Invalid radius %v km", km))
+	}
+	var labels []Label
+	for _, box := range protocol.RadiusBoxes(center, km) {
+		found, err := self.listRegion(box, func(point protocol.GeoPoint) bool {
+			return protocol.DistanceKm(center, point) <= km
+		})
+		if err != nil {
+			return nil, err
+		}
+		labels = append(labels, found...)
+	}
+	return labels, nil
 }
 
-func (self WineLabelClient) Show(labelID string) (string, error) {
-	apiSuffix := fmt.Sprintf("%s/%s", STATE_API, self.getAddress(labelID))
-	response, err := self.sendRequest(apiSuffix, []byte{}, "", labelID)
+func (self WineLabelClient) listRegion(
+	box protocol.BoundingBox, inside func(protocol.GeoPoint) bool) ([]Label, error) {
+	cells, err := protocol.CoveringCells(box)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	var labels []Label
+	for _, cell := range cells {
+		indexed, err := self.listIndex(self.namespace.GeoIndexPrefix(cell))
+		if err != nil {
+			return nil, err
+		}
+		for _, label := range indexed {
+			if inside(label.Position) {
+				labels = append(labels, label)
+			}
+		}
+	}
+	return labels, nil
+}
+
+// listIndex fetches the index entries under an index prefix and then each
+// label they name.
+func (self WineLabelClient) listIndex(prefix string) ([]Label, error) {
+	entries, err := self.listState(prefix)
+	if err != nil {
+		return nil, err
+	}
+	labels := make([]protocol.LabelRecord, 0, len(entries))
+	for _, entry := range entries {
+		var index protocol.IndexEntry
+		if err := protocol.DecodeRecord(entry.Data, &index); err != nil {
+			return nil, errors.New(fmt.Sprintf("Error binary decoding: %v", err))
+		}
+		label, err := self.showRecord(index.WineLabelID)
+		if err != nil {
+			return nil, err
+		}
+		labels = append(labels, label)
+	}
+	return self.withRecalls(labels)
+}
+
+// readState fetches the data stored at a single address; name identifies
+// the record in errors.
+func (self WineLabelClient) readState(address string, name string) ([]byte, error) {
+	apiSuffix := fmt.Sprintf("%s/%s", STATE_API, address)
+	response, err := self.sendRequest(apiSuffix, []byte{}, "", name)
+	if err != nil {
+		return nil, err
 	}
 	responseMap := make(map[interface{}]interface{})
 	err = yaml.Unmarshal([]byte(response), &responseMap)
 	if err != nil {
-		return "", errors.New(fmt.Sprint("Error reading response: %v", err))
+		return nil, errors.New(fmt.Sprintf("Error reading response: %v", err))
 	}
 	data, ok := responseMap["data"].(string)
 	if !ok {
-		return "", errors.New("Error reading as string")
+		return nil, errors.New("Error reading as string")
 	}
 	responseData, err := base64.StdEncoding.DecodeString(data)
 	if err != nil {
-		return "", errors.New(fmt.Sprint("Error decoding response: %v", err))
+		return nil, errors.New(fmt.Sprintf("Error decoding response: %v", err))
 	}
-	var responseFinal WineLabelPayload
-	err = cbor.Loads(responseData, &responseFinal)
-	if err != nil {
-		return "", errors.New(fmt.Sprint("Error binary decoding: %v", err))
-	}
-	return fmt.Sprintf("%v", responseFinal.WineLabelID), nil
+	return responseData, nil
 }
 
 func (self WineLabelClient) getStatus(
@@ -170,14 +629,28 @@ func (self WineLabelClient) getStatus(
 		return "", err
 	}
 
-	responseMap := make(map[interface{}]interface{})
-	err = yaml.Unmarshal([]byte(response), &responseMap)
-	if err != nil {
+	var statuses struct {
+		Data []struct {
+			Status              string `yaml:"status"`
+			InvalidTransactions []struct {
+				ID      string `yaml:"id"`
+				Message string `yaml:"message"`
+			} `yaml:"invalid_transactions"`
+		} `yaml:"data"`
+	}
+	err = yaml.Unmarshal([]byte(response), &statuses)
+	if err != nil || len(statuses.Data) == 0 {
 		return "", errors.New(fmt.Sprintf("Error reading response: %v", err))
 	}
-	entry :=
-		responseMap["data"].([]interface{})[0].(WineLabelPayload)
-	return fmt.Sprint(entry.WineLabelID), nil
+	status := statuses.Data[0]
+	for _, invalid := range status.InvalidTransactions {
+		logrus.WithFields(logrus.Fields{
+			"batch_id":  batchId,
+			"signature": invalid.ID,
+			"message":   invalid.Message,
+		}).Warn("Transaction rejected")
+	}
+	return status.Status, nil
 }
 
 func (self WineLabelClient) sendRequest(
@@ -193,33 +666,30 @@ func (self WineLabelClient) sendRequest(
 	} else {
 		url = fmt.Sprintf("http://%s/%s", self.url, apiSuffix)
 	}
-	fmt.Println("URL :", url)
-	fmt.Println("data :", data)
+	log := logrus.WithFields(logrus.Fields{"url": url, "bytes": len(data)})
+	log.Debug("Sending request")
 
 	// Send request to validator URL
 	var response *http.Response
 	var err error
 	if len(data) > 0 {
 		response, err = http.Post(url, contentType, bytes2.NewBuffer(data))
-		fmt.Println(response)
 	} else {
 		response, err = http.Get(url)
 	}
 	if err != nil {
-		fmt.Println(err)
 		return "", errors.New(
 			fmt.Sprintf("Failed to connect to REST API: %v", err))
 	}
 	if response.StatusCode == 404 {
-		return "", errors.New(fmt.Sprintf("No such key: %s", name))
+		return "", noSuchKey(name)
 	} else if response.StatusCode >= 400 {
 		return "", errors.New(
 			fmt.Sprintf("Error %d: %s", response.StatusCode, response.Status))
 	}
 	defer response.Body.Close()
 	reponseBody, err := ioutil.ReadAll(response.Body)
-	fmt.Println("Resposen body -- ")
-	fmt.Println(reponseBody)
+	log.WithFields(logrus.Fields{"status": response.StatusCode, "response": string(reponseBody)}).Debug("Received response")
 	if err != nil {
 		return "", errors.New(fmt.Sprintf("Error reading response: %v", err))
 	}
@@ -227,41 +697,31 @@ func (self WineLabelClient) sendRequest(
 }
 
 func (self WineLabelClient) sendTransaction(
-	verb string, labelID string, location, long, lat string, wait uint) (string, error) {
-	// construct the payload information in CBOR format
-	payloadData := WineLabelPayload{}
-	payloadData.Verb = verb
-	payloadData.WineLabelID = labelID
-	payloadData.PrintedAt = location
-	payloadData.Longitude = long
-	payloadData.Lattitude = lat
-	payload, err := cbor.Dumps(payloadData)
-
-	fmt.Println("--------")
-	fmt.Println(payloadData.WineLabelID)
-
+	payloadData protocol.WineLabelPayload, wait uint) (string, error) {
+	labelID := payloadData.WineLabelID
+	if payloadData.ClaimedAt == "" {
+		payloadData.ClaimedAt = time.Now().UTC().Format(time.RFC3339)
+	}
+	// construct the payload information in the client's encoding
+	payload, err := protocol.EncodePayloadAs(self.familyVersion, self.encoding, payloadData)
 	if err != nil {
-		return "", errors.New(fmt.Sprintf("Failed to construct CBOR: %v", err))
+		return "", errors.New(fmt.Sprintf("Failed to construct payload: %v", err))
 	}
 
-	// construct the address
-	address := self.getAddress(labelID)
-
-	fmt.Println("-------- address")
-	fmt.Println(address)
-	fmt.Println(self.signer.GetPublicKey().AsHex())
+	// construct the addresses
+	inputs, outputs := protocol.TransactionAddresses(self.familyName, self.PublicKey(), payloadData)
 
 	// Construct TransactionHeader
 	rawTransactionHeader := transaction_pb2.TransactionHeader{
 		SignerPublicKey:  self.signer.GetPublicKey().AsHex(),
-		FamilyName:       FAMILY_NAME,
-		FamilyVersion:    FAMILY_VERSION,
+		FamilyName:       self.familyName,
+		FamilyVersion:    self.familyVersion,
 		Dependencies:     []string{}, // empty dependency list
 		Nonce:            strconv.Itoa(rand.Int()),
 		BatcherPublicKey: self.signer.GetPublicKey().AsHex(),
-		Inputs:           []string{address},
-		Outputs:          []string{address},
-		PayloadSha512:    Sha512HashValue(string(payload)),
+		Inputs:           inputs,
+		Outputs:          outputs,
+		PayloadSha512:    protocol.Hexdigest(string(payload)),
 	}
 	transactionHeader, err := proto.Marshal(&rawTransactionHeader)
 	if err != nil {
@@ -289,17 +749,23 @@ func (self WineLabelClient) sendTransaction(
 	}
 	batchId := rawBatchList.Batches[0].HeaderSignature
 	batchList, err := proto.Marshal(&rawBatchList)
-	fmt.Println("Batch ID:", batchId)
 	if err != nil {
 		return "", errors.New(
 			fmt.Sprintf("Unable to serialize batch list: %v", err))
 	}
 
-	fmt.Println("--- Batch list ---")
-	fmt.Println(batchList)
+	// The processor tags its log entries with the same signature.
+	logrus.WithFields(logrus.Fields{
+		"family":    self.familyName,
+		"signature": transactionHeaderSignature,
+		"signer":    self.PublicKey(),
+		"verb":      payloadData.Verb,
+		"label_id":  labelID,
+		"batch_id":  batchId,
+		"inputs":    inputs,
+		"outputs":   outputs,
+	}).Info("Submitting transaction")
 	if wait > 0 {
-
-		fmt.Println("Entered")
 		waitTime := uint(0)
 		startTime := time.Now()
 		response, err := self.sendRequest(
@@ -317,8 +783,6 @@ func (self WineLabelClient) sendTransaction(
 				return response, nil
 			}
 		}
-		fmt.Println("Respoonse:")
-		fmt.Println(response)
 		return response, nil
 	}
 
@@ -326,16 +790,6 @@ func (self WineLabelClient) sendTransaction(
 		BATCH_SUBMIT_API, batchList, CONTENT_TYPE_OCTET_STREAM, labelID)
 }
 
-func (self WineLabelClient) getPrefix() string {
-	return Sha512HashValue(FAMILY_NAME)[:FAMILY_NAMESPACE_ADDRESS_LENGTH]
-}
-
-func (self WineLabelClient) getAddress(name string) string {
-	prefix := self.getPrefix()
-	nameAddress := Sha512HashValue(name)[FAMILY_VERB_ADDRESS_LENGTH:]
-	return prefix + nameAddress
-}
-
 func (self WineLabelClient) createBatchList(
 	transactions []*transaction_pb2.Transaction) (batch_pb2.BatchList, error) {
 
@@ -374,12 +828,6 @@ func (self WineLabelClient) createBatchList(
 	}, nil
 }
 
-func Sha512HashValue(value string) string {
-	hashHandler := sha512.New()
-	hashHandler.Write([]byte(value))
-	return strings.ToLower(hex.EncodeToString(hashHandler.Sum(nil)))
-}
-
 func GetKeyfile(keyfile string) (string, error) {
 	if keyfile == "" {
 		username, err := user.Current()
@@ -0,0 +1,66 @@
+package client
+
+import (
+	"github.com/jessevdk/go-flags"
+
+	"wine-label-protocol/protocol"
+)
+
+type CreateLot struct {
+	Args struct {
+		Id      string `positional-arg-name:"id" required:"true" description:"id of the lot"`
+		Bottles uint64 `positional-arg-name:"bottles" required:"true" description:"number of bottles in the lot"`
+	} `positional-args:"true"`
+	Vintage     uint64   `long:"vintage" description:"Harvest year, omitted for non-vintage wine"`
+	Varietals   []string `long:"varietal" description:"Grape and its share as grape:percent, may be repeated"`
+	Appellation string   `long:"appellation" description:"Appellation of the wine"`
+	Url         string   `long:"url" description:"Specify URL of REST API"`
+	Keyfile     string   `long:"keyfile" description:"Identify file containing user's private key"`
+	Wait        uint     `long:"wait" description:"Set time, in seconds, to wait for transaction to commit"`
+}
+
+func (args *CreateLot) Name() string {
+	return "create-lot"
+}
+
+func (args *CreateLot) KeyfilePassed() string {
+	return args.Keyfile
+}
+
+func (args *CreateLot) UrlPassed() string {
+	return args.Url
+}
+
+func (args *CreateLot) Register(parent *flags.Command) error {
+	_, err := parent.AddCommand(args.Name(), "Declares a lot of wine", "Sends a transaction declaring the lot <id> of <bottles> bottles for the signer's winery.", args)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (args *CreateLot) Run() error {
+	// Construct client
+	wait := args.Wait
+
+	lot := protocol.LotPayload{
+		LotID:       args.Args.Id,
+		Vintage:     args.Vintage,
+		Appellation: args.Appellation,
+		BottleCount: args.Args.Bottles,
+	}
+	for _, str := range args.Varietals {
+		varietal, err := protocol.ParseVarietal(str)
+		if err != nil {
+			return err
+		}
+		lot.Varietals = append(lot.Varietals, varietal)
+	}
+
+	WineLabelClient, err := GetClient(args, true)
+	if err != nil {
+		return err
+	}
+	_, err = WineLabelClient.CreateLot(lot, wait)
+	return err
+}
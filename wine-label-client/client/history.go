@@ -0,0 +1,56 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/jessevdk/go-flags"
+)
+
+type History struct {
+	Args struct {
+		Id string `positional-arg-name:"id" required:"true" description:"id of the wine label"`
+	} `positional-args:"true"`
+	Url string `long:"url" description:"Specify URL of REST API"`
+}
+
+func (args *History) Name() string {
+	return "history"
+}
+
+func (args *History) KeyfilePassed() string {
+	return ""
+}
+
+func (args *History) UrlPassed() string {
+	return args.Url
+}
+
+func (args *History) Register(parent *flags.Command) error {
+	_, err := parent.AddCommand(args.Name(), "Displays the provenance of a wine label", "Lists every recorded change to the wine label <id> after verifying the history chain.", args)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (args *History) Run() error {
+	// Construct client
+	id := args.Args.Id
+
+	WineLabelClient, err := GetClient(args, false)
+	if err != nil {
+		return err
+	}
+	events, err := WineLabelClient.History(id)
+	if err != nil {
+		return err
+	}
+	for _, event := range events {
+		fmt.Printf("%d %v %v by %v at %v", event.Sequence, event.ClaimedAt, event.Verb, event.Signer, event.Location)
+		if event.HasPosition {
+			fmt.Printf(" (%v)", event.Position)
+		}
+		fmt.Println()
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jessevdk/go-flags"
+
+	"wine-label-protocol/protocol"
+)
+
+type List struct {
+	Owner        string  `long:"owner" description:"List the labels owned by this public key"`
+	Lot          string  `long:"lot" description:"List the labels attached to this lot"`
+	Facility     string  `long:"facility" description:"List the labels minted at this facility"`
+	Organisation string  `long:"org" description:"List the labels owned by this organisation"`
+	Box          string  `long:"box" description:"List the labels printed within south,west,north,east"`
+	Near         string  `long:"near" description:"List the labels printed within --km of lat,long"`
+	Km           float64 `long:"km" default:"10" description:"Radius of --near in kilometres"`
+	Url          string  `long:"url" description:"Specify URL of REST API"`
+}
+
+func (args *List) Name() string {
+	return "list"
+}
+
+func (args *List) KeyfilePassed() string {
+	return ""
+}
+
+func (args *List) UrlPassed() string {
+	return args.Url
+}
+
+func (args *List) Register(parent *flags.Command) error {
+	_, err := parent.AddCommand(args.Name(), "Lists wine labels", "Lists the wine labels matching at most one of --owner, --lot, --facility, --org, --box or --near, or every label.", args)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (args *List) Run() error {
+	// Construct client
+	WineLabelClient, err := GetClient(args, false)
+	if err != nil {
+		return err
+	}
+
+	filters := 0
+	for _, filter := range []string{args.Owner, args.Lot, args.Facility, args.Organisation, args.Box, args.Near} {
+		if filter != "" {
+			filters++
+		}
+	}
+	if filters > 1 {
+		return errors.New("Only one of --owner, --lot, --facility, --org, --box and --near may be given")
+	}
+
+	var labels []Label
+	switch {
+	case args.Owner != "":
+		labels, err = WineLabelClient.ListByOwner(args.Owner)
+	case args.Lot != "":
+		labels, err = WineLabelClient.ListByLot(args.Lot)
+	case args.Facility != "":
+		labels, err = WineLabelClient.ListByFacility(args.Facility)
+	case args.Organisation != "":
+		labels, err = WineLabelClient.ListByOrganisation(args.Organisation)
+	case args.Box != "":
+		var corners []protocol.GeoPoint
+		corners, err = parsePoints(args.Box, 2)
+		if err == nil {
+			labels, err = WineLabelClient.ListInBox(protocol.BoundingBox{Min: corners[0], Max: corners[1]})
+		}
+	case args.Near != "":
+		var center []protocol.GeoPoint
+		center, err = parsePoints(args.Near, 1)
+		if err == nil {
+			labels, err = WineLabelClient.ListWithin(center[0], args.Km)
+		}
+	default:
+		labels, err = WineLabelClient.List()
+	}
+	if err != nil {
+		return err
+	}
+	for _, label := range labels {
+		fmt.Printf("%v: %v, owner %v\n", label.WineLabelID, label.Status, label.Owner)
+		if label.Recall != nil {
+			fmt.Printf("  RECALLED: lot %v %v\n", label.Lot, *label.Recall)
+		}
+	}
+	return nil
+}
+
+// parsePoints parses count points written as comma separated latitude and
+// longitude pairs.
+func parsePoints(str string, count int) ([]protocol.GeoPoint, error) {
+	fields := strings.Split(str, ",")
+	if len(fields) != 2*count {
+		return nil, errors.New(fmt.Sprintf("Expected %d coordinates in %q", 2*count, str))
+	}
+	points := make([]protocol.GeoPoint, 0, count)
+	for i := 0; i < len(fields); i += 2 {
+		point, err := protocol.ParseGeoPoint(fields[i], fields[i+1])
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, point)
+	}
+	return points, nil
+}
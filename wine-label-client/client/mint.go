@@ -0,0 +1,122 @@
+package client
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jessevdk/go-flags"
+
+	"wine-label-protocol/protocol"
+)
+
+type Mint struct {
+	Args struct {
+		Ids []string `positional-arg-name:"id" description:"ids of the labels to mint, instead of a range"`
+	} `positional-args:"true"`
+	Prefix    string `long:"prefix" description:"Prefix of the label ids in a range"`
+	First     uint64 `long:"first" description:"Number of the first label in a range"`
+	Count     uint64 `long:"count" description:"Number of labels in a range"`
+	Digits    uint64 `long:"digits" description:"Zero-pad range numbers to this many digits"`
+	PrintedAt string `long:"printed-at" description:"RFC 3339 time the roll was printed, now if not given"`
+	Printer   string `long:"printer" required:"true" description:"ID of the printing device"`
+	Facility  string `long:"facility" required:"true" description:"ID of the facility the printer is at"`
+	Operator  string `long:"operator" required:"true" description:"Who ran the print"`
+	Location  string `long:"location" description:"Description of where the roll was printed"`
+	Long      string `long:"long" description:"Longitude of the print facility"`
+	Lat       string `long:"lat" description:"Latitude of the print facility"`
+	Lot       string `long:"lot" description:"Lot the labels are for"`
+	Secrets   string `long:"secrets" description:"File of scratch-off secrets, one \"id secret\" pair per line"`
+	Url       string `long:"url" description:"Specify URL of REST API"`
+	Keyfile   string `long:"keyfile" description:"Identify file containing user's private key"`
+	Wait      uint   `long:"wait" description:"Set time, in seconds, to wait for transaction to commit"`
+}
+
+func (args *Mint) Name() string {
+	return "mint"
+}
+
+func (args *Mint) KeyfilePassed() string {
+	return args.Keyfile
+}
+
+func (args *Mint) UrlPassed() string {
+	return args.Url
+}
+
+func (args *Mint) Register(parent *flags.Command) error {
+	_, err := parent.AddCommand(args.Name(), "Mints a roll of labels", "Sends a transaction creating the labels <id>..., or --count labels numbered from --first.", args)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (args *Mint) Run() error {
+	// Construct client
+	wait := args.Wait
+
+	WineLabelClient, err := GetClient(args, true)
+	if err != nil {
+		return err
+	}
+	printedAt := args.PrintedAt
+	if printedAt == "" {
+		printedAt = time.Now().UTC().Format(time.RFC3339)
+	}
+	mint := protocol.MintPayload{
+		LabelIDs: args.Args.Ids,
+		Prefix:   args.Prefix,
+		First:    args.First,
+		Count:    args.Count,
+		Digits:   args.Digits,
+		Print: protocol.PrintEvent{
+			Time:     printedAt,
+			Printer:  args.Printer,
+			Facility: args.Facility,
+			Operator: args.Operator,
+		},
+		PrintedAt: args.Location,
+		Longitude: args.Long,
+		Lattitude: args.Lat,
+		Lot:       args.Lot,
+	}
+	if args.Secrets != "" {
+		mint.Secrets, err = readSecrets(args.Secrets)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = WineLabelClient.Mint(mint, wait)
+	return err
+}
+
+// readSecrets reads a file of label IDs and the secrets printed under
+// their scratch-offs, one pair per line separated by white space, and
+// salts and hashes each secret.
+func readSecrets(name string) (map[string]protocol.LabelSecret, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	secrets := make(map[string]protocol.LabelSecret)
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, errors.New(fmt.Sprintf("%v:%d: expected a label id and a secret", name, line))
+		}
+		secrets[fields[0]], err = protocol.NewLabelSecret(fields[1])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return secrets, scanner.Err()
+}
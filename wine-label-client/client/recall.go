@@ -0,0 +1,49 @@
+package client
+
+import (
+	"github.com/jessevdk/go-flags"
+)
+
+type Recall struct {
+	Args struct {
+		Id     string `positional-arg-name:"id" required:"true" description:"id of the lot"`
+		Reason string `positional-arg-name:"reason" required:"true" description:"why the lot is recalled"`
+	} `positional-args:"true"`
+	Url     string `long:"url" description:"Specify URL of REST API"`
+	Keyfile string `long:"keyfile" description:"Identify file containing user's private key"`
+	Wait    uint   `long:"wait" description:"Set time, in seconds, to wait for transaction to commit"`
+}
+
+func (args *Recall) Name() string {
+	return "recall"
+}
+
+func (args *Recall) KeyfilePassed() string {
+	return args.Keyfile
+}
+
+func (args *Recall) UrlPassed() string {
+	return args.Url
+}
+
+func (args *Recall) Register(parent *flags.Command) error {
+	_, err := parent.AddCommand(args.Name(), "Recalls a lot", "Sends a transaction recalling every bottle of the lot <id> for <reason>. Must be signed by the lot's producer or a regulator.", args)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (args *Recall) Run() error {
+	// Construct client
+	id := args.Args.Id
+	reason := args.Args.Reason
+	wait := args.Wait
+
+	WineLabelClient, err := GetClient(args, true)
+	if err != nil {
+		return err
+	}
+	_, err = WineLabelClient.Recall(id, reason, wait)
+	return err
+}
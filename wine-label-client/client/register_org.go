@@ -0,0 +1,57 @@
+package client
+
+import (
+	"github.com/jessevdk/go-flags"
+
+	"wine-label-protocol/protocol"
+)
+
+type RegisterOrg struct {
+	Args struct {
+		Id   string `positional-arg-name:"id" required:"true" description:"id of the organisation"`
+		Type string `positional-arg-name:"type" required:"true" description:"one of winery, printer, distributor, retailer"`
+	} `positional-args:"true"`
+	OrgName string   `long:"name" description:"Display name of the organisation"`
+	Keys    []string `long:"key" description:"Public key authorised for the organisation, may be repeated"`
+	Url     string   `long:"url" description:"Specify URL of REST API"`
+	Keyfile string   `long:"keyfile" description:"Identify file containing user's private key"`
+	Wait    uint     `long:"wait" description:"Set time, in seconds, to wait for transaction to commit"`
+}
+
+func (args *RegisterOrg) Name() string {
+	return "register-org"
+}
+
+func (args *RegisterOrg) KeyfilePassed() string {
+	return args.Keyfile
+}
+
+func (args *RegisterOrg) UrlPassed() string {
+	return args.Url
+}
+
+func (args *RegisterOrg) Register(parent *flags.Command) error {
+	_, err := parent.AddCommand(args.Name(), "Registers an organisation", "Sends a transaction adding the organisation <id> of <type> to the registry.", args)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (args *RegisterOrg) Run() error {
+	// Construct client
+	wait := args.Wait
+
+	WineLabelClient, err := GetClient(args, true)
+	if err != nil {
+		return err
+	}
+	org := protocol.OrgPayload{
+		OrgID: args.Args.Id,
+		Name:  args.OrgName,
+		Type:  args.Args.Type,
+		Keys:  args.Keys,
+	}
+	_, err = WineLabelClient.RegisterOrganisation(org, wait)
+	return err
+}
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/jessevdk/go-flags"
+)
+
+type Scan struct {
+	Args struct {
+		Id       string `positional-arg-name:"id" required:"true" description:"id of the wine label"`
+		Location string `positional-arg-name:"location" required:"true" description:"where the label was scanned"`
+		Long     string `positional-arg-name:"long" required:"true" description:"long"`
+		Lat      string `positional-arg-name:"lat" required:"true" description:"lat"`
+	} `positional-args:"true"`
+	Url     string `long:"url" description:"Specify URL of REST API"`
+	Keyfile string `long:"keyfile" description:"Identify file containing user's private key"`
+	Wait    uint   `long:"wait" description:"Set time, in seconds, to wait for transaction to commit"`
+}
+
+func (args *Scan) Name() string {
+	return "scan"
+}
+
+func (args *Scan) KeyfilePassed() string {
+	return args.Keyfile
+}
+
+func (args *Scan) UrlPassed() string {
+	return args.Url
+}
+
+func (args *Scan) Register(parent *flags.Command) error {
+	_, err := parent.AddCommand(args.Name(), "Records a scan of a wine label", "Sends a transaction recording that the wine label <id> was scanned at <location>, <long>, <lat>.", args)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (args *Scan) Run() error {
+	// Construct client
+	id := args.Args.Id
+	location := args.Args.Location
+	long := args.Args.Long
+	lat := args.Args.Lat
+
+	wait := args.Wait
+
+	WineLabelClient, err := GetClient(args, true)
+	if err != nil {
+		return err
+	}
+	_, err = WineLabelClient.Scan(id, location, long, lat, wait)
+	if err != nil {
+		return err
+	}
+	label, err := WineLabelClient.Show(id)
+	if err != nil {
+		return err
+	}
+	if label.Recall != nil {
+		fmt.Printf("RECALLED: lot %v %v\n", label.Lot, *label.Recall)
+	}
+	if label.Suspicious {
+		fmt.Printf("SUSPICIOUS: %v\n", label.SuspiciousReason)
+	}
+	return nil
+}
@@ -1,7 +1,11 @@
 package client
 
 import (
+	"time"
+
 	"github.com/jessevdk/go-flags"
+
+	"wine-label-protocol/protocol"
 )
 
 // All subcommands implement this interface
@@ -15,14 +19,20 @@ type Command interface {
 
 type Set struct {
 	Args struct {
-		Id       string `positional-arg-name:"id" required:"true" description:"id of the wine label"`
-		Location string `positional-arg-name:"location" required:"true" description:"location"`
-		Long     string `positional-arg-name:"long" required:"true" description:"long"`
-		Lat      string `positional-arg-name:"lat" required:"true" description:"lat"`
+		Id string `positional-arg-name:"id" required:"true" description:"id of the wine label"`
 	} `positional-args:"true"`
-	Url     string `long:"url" description:"Specify URL of REST API"`
-	Keyfile string `long:"keyfile" description:"Identify file containing user's private key"`
-	Wait    uint   `long:"wait" description:"Set time, in seconds, to wait for transaction to commit"`
+	Long      string `long:"long" required:"true" description:"Longitude the label was printed at"`
+	Lat       string `long:"lat" required:"true" description:"Latitude the label was printed at"`
+	PrintedAt string `long:"printed-at" description:"RFC 3339 time the label was printed, now if not given"`
+	Printer   string `long:"printer" required:"true" description:"ID of the printing device"`
+	Facility  string `long:"facility" required:"true" description:"ID of the facility the printer is at"`
+	Operator  string `long:"operator" required:"true" description:"Who ran the print"`
+	Location  string `long:"location" description:"Description of where the label was printed"`
+	Lot       string `long:"lot" description:"Lot the label is attached to"`
+	Secret    string `long:"secret" description:"Secret printed under the label's scratch-off"`
+	Url       string `long:"url" description:"Specify URL of REST API"`
+	Keyfile   string `long:"keyfile" description:"Identify file containing user's private key"`
+	Wait      uint   `long:"wait" description:"Set time, in seconds, to wait for transaction to commit"`
 }
 
 func (args *Set) Name() string {
@@ -38,7 +48,7 @@ func (args *Set) UrlPassed() string {
 }
 
 func (args *Set) Register(parent *flags.Command) error {
-	_, err := parent.AddCommand(args.Name(), "Sets an intkey value", "Sends an intkey transaction to set <name> to <value>.", args)
+	_, err := parent.AddCommand(args.Name(), "Prints a wine label", "Sends a transaction recording that the wine label <id> was printed, where, when and by whom.", args)
 	if err != nil {
 		return err
 	}
@@ -47,10 +57,23 @@ func (args *Set) Register(parent *flags.Command) error {
 
 func (args *Set) Run() error {
 	// Construct client
-	id := args.Args.Id
-	location := args.Args.Location
-	long := args.Args.Long
-	lat := args.Args.Lat
+	printedAt := args.PrintedAt
+	if printedAt == "" {
+		printedAt = time.Now().UTC().Format(time.RFC3339)
+	}
+	label := protocol.Payload{
+		WineLabelID: args.Args.Id,
+		PrintedAt:   args.Location,
+		Longitude:   args.Long,
+		Lattitude:   args.Lat,
+		Lot:         args.Lot,
+		Print: protocol.PrintEvent{
+			Time:     printedAt,
+			Printer:  args.Printer,
+			Facility: args.Facility,
+			Operator: args.Operator,
+		},
+	}
 
 	wait := args.Wait
 
@@ -58,10 +81,19 @@ func (args *Set) Run() error {
 	if err != nil {
 		return err
 	}
-	_, err = WineLabelClient.Set(id, location, long, lat, wait)
+	_, err = WineLabelClient.Set(label, args.Secret, wait)
 	return err
 }
 
+// FamilyVersion is the wine-label family version GetClient's clients send.
+var FamilyVersion string = protocol.FAMILY_VERSION
+
+// Encoding is the payload encoding GetClient's clients send.
+var Encoding protocol.Encoding = protocol.ENCODING_CBOR
+
+// Tenant is the consortium tenant GetClient's clients work with.
+var Tenant string = protocol.DEFAULT_TENANT
+
 func GetClient(args Command, readFile bool) (WineLabelClient, error) {
 	url := args.UrlPassed()
 	if url == "" {
@@ -75,5 +107,17 @@ func GetClient(args Command, readFile bool) (WineLabelClient, error) {
 			return WineLabelClient{}, err
 		}
 	}
-	return NewWineLabelClient(url, keyfile)
+	client, err := NewWineLabelClient(url, keyfile)
+	if err != nil {
+		return client, err
+	}
+	client, err = client.WithFamilyVersion(FamilyVersion)
+	if err != nil {
+		return client, err
+	}
+	client, err = client.WithTenant(Tenant)
+	if err != nil {
+		return client, err
+	}
+	return client.WithEncoding(Encoding)
 }
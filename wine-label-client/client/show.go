@@ -0,0 +1,77 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/jessevdk/go-flags"
+)
+
+type Show struct {
+	Args struct {
+		Id string `positional-arg-name:"id" required:"true" description:"id of the wine label"`
+	} `positional-args:"true"`
+	Url string `long:"url" description:"Specify URL of REST API"`
+}
+
+func (args *Show) Name() string {
+	return "show"
+}
+
+func (args *Show) KeyfilePassed() string {
+	return ""
+}
+
+func (args *Show) UrlPassed() string {
+	return args.Url
+}
+
+func (args *Show) Register(parent *flags.Command) error {
+	_, err := parent.AddCommand(args.Name(), "Displays a wine label", "Shows the stored record of the wine label <id>.", args)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (args *Show) Run() error {
+	// Construct client
+	id := args.Args.Id
+
+	WineLabelClient, err := GetClient(args, false)
+	if err != nil {
+		return err
+	}
+	record, err := WineLabelClient.Show(id)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%v: %v, printed at %v (%v)\n",
+		record.WineLabelID, record.Status, record.PrintedAt, record.Position)
+	if record.PrintTime != "" {
+		fmt.Printf("printed %v on %v at %v by %v\n",
+			record.PrintTime, record.Printer, record.Facility, record.Operator)
+	}
+	fmt.Printf("owner: %v\n", record.Owner)
+	if record.PendingOwner != "" {
+		fmt.Printf("offered to: %v\n", record.PendingOwner)
+	}
+	if record.Lot != "" {
+		fmt.Printf("lot: %v\n", record.Lot)
+	}
+	if record.Recall != nil {
+		fmt.Printf("RECALLED: lot %v %v\n", record.Lot, *record.Recall)
+	}
+	if record.ScanCount > 0 {
+		fmt.Printf("scanned %d times, last at %v (%v) by %v\n", record.ScanCount,
+			record.LastScan.ScannedAt, record.LastScan.Position, record.LastScan.Scanner)
+	}
+	if record.Claim != nil {
+		fmt.Printf("claimed by %v at %v\n", record.Claim.Claimant, record.Claim.ClaimedAt)
+	} else if record.Secret.IsSet() {
+		fmt.Printf("unclaimed, has a scratch-off secret\n")
+	}
+	if record.Suspicious {
+		fmt.Printf("SUSPICIOUS: %v\n", record.SuspiciousReason)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/jessevdk/go-flags"
+)
+
+type ShowLot struct {
+	Args struct {
+		Id string `positional-arg-name:"id" required:"true" description:"id of the lot"`
+	} `positional-args:"true"`
+	Url string `long:"url" description:"Specify URL of REST API"`
+}
+
+func (args *ShowLot) Name() string {
+	return "show-lot"
+}
+
+func (args *ShowLot) KeyfilePassed() string {
+	return ""
+}
+
+func (args *ShowLot) UrlPassed() string {
+	return args.Url
+}
+
+func (args *ShowLot) Register(parent *flags.Command) error {
+	_, err := parent.AddCommand(args.Name(), "Displays a lot and its labels", "Shows the lot <id> and every label attached to it.", args)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (args *ShowLot) Run() error {
+	// Construct client
+	id := args.Args.Id
+
+	WineLabelClient, err := GetClient(args, false)
+	if err != nil {
+		return err
+	}
+	lot, err := WineLabelClient.ShowLot(id)
+	if err != nil {
+		return err
+	}
+	labels, err := WineLabelClient.ListByLot(id)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%v: %v %v by %v\n", lot.LotID, lot.Appellation, lot.Vintage, lot.Producer)
+	for _, varietal := range lot.Varietals {
+		fmt.Printf("  %v %d%%\n", varietal.Grape, varietal.Percent)
+	}
+	fmt.Printf("labels: %d of %d bottles\n", lot.LabelCount, lot.BottleCount)
+	if lot.Recall != nil {
+		fmt.Printf("RECALLED by %v: %v\n", lot.Recall.RecalledBy, *lot.Recall)
+	}
+	for _, label := range labels {
+		fmt.Printf("  %v: %v\n", label.WineLabelID, label.Status)
+	}
+	return nil
+}
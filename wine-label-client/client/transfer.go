@@ -0,0 +1,49 @@
+package client
+
+import (
+	"github.com/jessevdk/go-flags"
+)
+
+type Transfer struct {
+	Args struct {
+		Id        string `positional-arg-name:"id" required:"true" description:"id of the wine label"`
+		Recipient string `positional-arg-name:"recipient" required:"true" description:"public key of the new custodian"`
+	} `positional-args:"true"`
+	Url     string `long:"url" description:"Specify URL of REST API"`
+	Keyfile string `long:"keyfile" description:"Identify file containing user's private key"`
+	Wait    uint   `long:"wait" description:"Set time, in seconds, to wait for transaction to commit"`
+}
+
+func (args *Transfer) Name() string {
+	return "transfer"
+}
+
+func (args *Transfer) KeyfilePassed() string {
+	return args.Keyfile
+}
+
+func (args *Transfer) UrlPassed() string {
+	return args.Url
+}
+
+func (args *Transfer) Register(parent *flags.Command) error {
+	_, err := parent.AddCommand(args.Name(), "Offers a wine label to a new custodian", "Sends a transaction offering the wine label <id> to <recipient>, who must accept it.", args)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (args *Transfer) Run() error {
+	// Construct client
+	id := args.Args.Id
+	recipient := args.Args.Recipient
+	wait := args.Wait
+
+	WineLabelClient, err := GetClient(args, true)
+	if err != nil {
+		return err
+	}
+	_, err = WineLabelClient.Transfer(id, recipient, wait)
+	return err
+}
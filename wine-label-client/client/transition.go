@@ -0,0 +1,49 @@
+package client
+
+import (
+	"github.com/jessevdk/go-flags"
+)
+
+type Transition struct {
+	Args struct {
+		Id   string `positional-arg-name:"id" required:"true" description:"id of the wine label"`
+		Verb string `positional-arg-name:"verb" required:"true" description:"one of apply, bottle, ship, stock, sell, open, void"`
+	} `positional-args:"true"`
+	Url     string `long:"url" description:"Specify URL of REST API"`
+	Keyfile string `long:"keyfile" description:"Identify file containing user's private key"`
+	Wait    uint   `long:"wait" description:"Set time, in seconds, to wait for transaction to commit"`
+}
+
+func (args *Transition) Name() string {
+	return "transition"
+}
+
+func (args *Transition) KeyfilePassed() string {
+	return args.Keyfile
+}
+
+func (args *Transition) UrlPassed() string {
+	return args.Url
+}
+
+func (args *Transition) Register(parent *flags.Command) error {
+	_, err := parent.AddCommand(args.Name(), "Moves a wine label along its lifecycle", "Sends a transaction to move the wine label <id> to the status named by <verb>.", args)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (args *Transition) Run() error {
+	// Construct client
+	id := args.Args.Id
+	verb := args.Args.Verb
+	wait := args.Wait
+
+	WineLabelClient, err := GetClient(args, true)
+	if err != nil {
+		return err
+	}
+	_, err = WineLabelClient.Transition(id, verb, wait)
+	return err
+}
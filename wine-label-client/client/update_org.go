@@ -0,0 +1,57 @@
+package client
+
+import (
+	"github.com/jessevdk/go-flags"
+
+	"wine-label-protocol/protocol"
+)
+
+type UpdateOrg struct {
+	Args struct {
+		Id   string `positional-arg-name:"id" required:"true" description:"id of the organisation"`
+		Type string `positional-arg-name:"type" required:"true" description:"current type of the organisation"`
+	} `positional-args:"true"`
+	OrgName string   `long:"name" description:"Display name of the organisation"`
+	Keys    []string `long:"key" description:"Public key authorised for the organisation, may be repeated; replaces the current keys"`
+	Url     string   `long:"url" description:"Specify URL of REST API"`
+	Keyfile string   `long:"keyfile" description:"Identify file containing user's private key"`
+	Wait    uint     `long:"wait" description:"Set time, in seconds, to wait for transaction to commit"`
+}
+
+func (args *UpdateOrg) Name() string {
+	return "update-org"
+}
+
+func (args *UpdateOrg) KeyfilePassed() string {
+	return args.Keyfile
+}
+
+func (args *UpdateOrg) UrlPassed() string {
+	return args.Url
+}
+
+func (args *UpdateOrg) Register(parent *flags.Command) error {
+	_, err := parent.AddCommand(args.Name(), "Updates an organisation", "Sends a transaction replacing the name and keys of the organisation <id>.", args)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (args *UpdateOrg) Run() error {
+	// Construct client
+	wait := args.Wait
+
+	WineLabelClient, err := GetClient(args, true)
+	if err != nil {
+		return err
+	}
+	org := protocol.OrgPayload{
+		OrgID: args.Args.Id,
+		Name:  args.OrgName,
+		Type:  args.Args.Type,
+		Keys:  args.Keys,
+	}
+	_, err = WineLabelClient.UpdateOrganisation(org, wait)
+	return err
+}
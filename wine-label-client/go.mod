@@ -3,9 +3,12 @@ module wine-client
 go 1.15
 
 require (
-	github.com/brianolson/cbor_go v1.0.0
 	github.com/golang/protobuf v1.4.3
 	github.com/hyperledger/sawtooth-sdk-go v0.1.4
 	github.com/jessevdk/go-flags v1.5.0
+	github.com/sirupsen/logrus v1.8.1
 	gopkg.in/yaml.v2 v2.4.0
+	wine-label-protocol v0.0.0
 )
+
+replace wine-label-protocol => ../wine-label-protocol
@@ -4,21 +4,25 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/hyperledger/sawtooth-sdk-go/logging"
 	flags "github.com/jessevdk/go-flags"
+	"github.com/sirupsen/logrus"
 
 	cl "wine-client/client"
+	"wine-label-protocol/protocol"
 )
 
 type Opts struct {
-	Verbose []bool `short:"v" long:"verbose" description:"Enable more verbose output"`
-	Version bool   `short:"V" long:"version" description:"Display version information"`
+	Verbose       []bool `short:"v" long:"verbose" description:"Enable more verbose output"`
+	Version       bool   `short:"V" long:"version" description:"Display version information"`
+	FamilyVersion string `long:"family-version" description:"Wine-label family version to send transactions as" default:"2.0" choice:"1.0" choice:"2.0"`
+	Encoding      string `long:"encoding" description:"Payload encoding; protobuf needs family version 2.0" default:"cbor" choice:"cbor" choice:"protobuf"`
+	Tenant        string `long:"tenant" description:"Consortium tenant whose labels to use" default:"default"`
+	Level         string `long:"log-level" description:"Log level; overrides -v" choice:"debug" choice:"info" choice:"warn" choice:"error"`
+	Format        string `long:"log-format" description:"Log format" default:"logfmt" choice:"logfmt" choice:"json"`
 }
 
 var DISTRIBUTION_VERSION string
 
-var logger *logging.Logger = logging.Get()
-
 func init() {
 	if len(DISTRIBUTION_VERSION) == 0 {
 		DISTRIBUTION_VERSION = "Unknown"
@@ -41,11 +45,27 @@ func main() {
 	// Add sub-commands
 	commands := []cl.Command{
 		&cl.Set{},
+		&cl.Mint{},
+		&cl.Delete{},
+		&cl.Transition{},
+		&cl.Show{},
+		&cl.List{},
+		&cl.History{},
+		&cl.Transfer{},
+		&cl.Accept{},
+		&cl.Cancel{},
+		&cl.Scan{},
+		&cl.Claim{},
+		&cl.CreateLot{},
+		&cl.ShowLot{},
+		&cl.Recall{},
+		&cl.RegisterOrg{},
+		&cl.UpdateOrg{},
 	}
 	for _, cmd := range commands {
 		err := cmd.Register(parser.Command)
 		if err != nil {
-			logger.Errorf("Couldn't register command %v: %v", cmd.Name(), err)
+			logrus.WithError(err).WithField("command", cmd.Name()).Error("Couldn't register command")
 			os.Exit(1)
 		}
 	}
@@ -64,14 +84,11 @@ func main() {
 		os.Exit(2)
 	}
 
-	switch len(opts.Verbose) {
-	case 2:
-		logger.SetLevel(logging.DEBUG)
-	case 1:
-		logger.SetLevel(logging.INFO)
-	default:
-		logger.SetLevel(logging.WARN)
-	}
+	configureLogging(opts.Level, opts.Format, len(opts.Verbose))
+
+	cl.FamilyVersion = opts.FamilyVersion
+	cl.Encoding = protocol.Encoding(opts.Encoding)
+	cl.Tenant = opts.Tenant
 
 	// If a sub-command was passed, run it
 	if parser.Command.Active == nil {
@@ -93,6 +110,29 @@ func main() {
 	fmt.Println("Error: Command not found: ", name)
 }
 
+// configureLogging sets the level and format of the client's logs, which
+// go to stderr. The level is warn, info or debug by the number of -v flags
+// unless given.
+func configureLogging(level string, format string, verbosity int) {
+	if level == "" {
+		switch {
+		case verbosity >= 2:
+			level = "debug"
+		case verbosity == 1:
+			level = "info"
+		default:
+			level = "warn"
+		}
+	}
+	parsed, _ := logrus.ParseLevel(level)
+	logrus.SetLevel(parsed)
+	if format == "json" {
+		logrus.SetFormatter(&logrus.JSONFormatter{})
+	} else {
+		logrus.SetFormatter(&logrus.TextFormatter{DisableColors: true, FullTimestamp: true})
+	}
+}
+
 func GetClient(args cl.Command, readFile bool) (cl.WineLabelClient, error) {
 	url := args.UrlPassed()
 	if url == "" {
@@ -0,0 +1,9 @@
+module wine-label-protocol
+
+go 1.15
+
+require (
+	github.com/fxamacker/cbor/v2 v2.4.0
+	github.com/golang/protobuf v1.4.3
+	google.golang.org/protobuf v1.25.0
+)
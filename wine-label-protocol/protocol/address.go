@@ -0,0 +1,91 @@
+package protocol
+
+import (
+	"fmt"
+)
+
+// Address layout of the wine-label namespace:
+//
+//	prefix(6) | space(2) | key(62)
+//
+// The two characters after the family prefix name the sub-namespace a
+// record lives in, so each kind of record can be listed by prefix without
+// picking up the others. The index spaces are described in index.go.
+//
+// Labels written by the original 1.0 processor sit outside this layout, at
+// their LegacyLabelAddress. Processors still read them there and move them
+// to their LabelAddress the next time they change, so labels on chain from
+// before the layout was introduced stay usable without a migration.
+const (
+	NAMESPACE_PREFIX_LENGTH int = 6
+	SPACE_LENGTH            int = 2
+	ADDRESS_LENGTH          int = 70
+	// History events end in their sequence number as 16 hex characters.
+	SEQUENCE_LENGTH int = 16
+
+	LABEL_SPACE   string = "00"
+	HISTORY_SPACE string = "01"
+	ORG_SPACE     string = "02"
+	KEY_SPACE     string = "03"
+	LOT_SPACE     string = "04"
+)
+
+// Namespace is the address prefix owned by a transaction family.
+type Namespace string
+
+func NewNamespace(familyName string) Namespace {
+	return Namespace(Hexdigest(familyName)[:NAMESPACE_PREFIX_LENGTH])
+}
+
+// SpacePrefix returns the address prefix of a sub-namespace.
+func (ns Namespace) SpacePrefix(space string) string {
+	return string(ns) + space
+}
+
+// LabelAddress returns the state address of the wine label with the given ID.
+func (ns Namespace) LabelAddress(labelID string) string {
+	return ns.spaceAddress(LABEL_SPACE, Hexdigest(labelID))
+}
+
+// LegacyLabelAddress returns the address the original processor stored a
+// label at: the family prefix followed by the last 64 characters of the
+// hash of its ID.
+func (ns Namespace) LegacyLabelAddress(labelID string) string {
+	hashed := Hexdigest(labelID)
+	return string(ns) + hashed[len(hashed)-(ADDRESS_LENGTH-NAMESPACE_PREFIX_LENGTH):]
+}
+
+// HistoryPrefix returns the address prefix under which every history event
+// of a label is stored.
+func (ns Namespace) HistoryPrefix(labelID string) string {
+	keyLength := ADDRESS_LENGTH - NAMESPACE_PREFIX_LENGTH - SPACE_LENGTH - SEQUENCE_LENGTH
+	return ns.SpacePrefix(HISTORY_SPACE) + Hexdigest(labelID)[:keyLength]
+}
+
+// HistoryAddress returns the address of a label's history event number
+// sequence. Events sort by address in the order they were appended.
+func (ns Namespace) HistoryAddress(labelID string, sequence uint64) string {
+	return fmt.Sprintf("%s%0*x", ns.HistoryPrefix(labelID), SEQUENCE_LENGTH, sequence)
+}
+
+// OrganisationAddress returns the address of an organisation's registry
+// record.
+func (ns Namespace) OrganisationAddress(orgID string) string {
+	return ns.spaceAddress(ORG_SPACE, Hexdigest(orgID))
+}
+
+// KeyAddress returns the address recording which organisation a public key
+// is registered to.
+func (ns Namespace) KeyAddress(publicKey string) string {
+	return ns.spaceAddress(KEY_SPACE, Hexdigest(publicKey))
+}
+
+// LotAddress returns the address of a lot record.
+func (ns Namespace) LotAddress(lotID string) string {
+	return ns.spaceAddress(LOT_SPACE, Hexdigest(lotID))
+}
+
+func (ns Namespace) spaceAddress(space string, hashed string) string {
+	keyLength := ADDRESS_LENGTH - NAMESPACE_PREFIX_LENGTH - SPACE_LENGTH
+	return ns.SpacePrefix(space) + hashed[:keyLength]
+}
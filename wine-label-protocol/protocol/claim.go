@@ -0,0 +1,92 @@
+package protocol
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+)
+
+// VERB_CLAIM reveals the secret printed under a label's scratch-off. A
+// label whose secret matches is marked claimed by the signer, once. Claims
+// with a wrong secret, and every claim after the first, are recorded in
+// the label's history and announced by EVENT_CLAIM_REJECTED but change
+// nothing else. The payload's ClaimedAt is the time of the claim.
+const VERB_CLAIM string = "claim"
+
+const (
+	// SALT_LENGTH is the length of the random salts NewLabelSecret makes,
+	// in hex characters; shorter salts are rejected.
+	SALT_LENGTH int = 32
+	// SECRET_HASH_LENGTH is the length of a SecretHash in hex characters.
+	SECRET_HASH_LENGTH int = 128
+	// MIN_SECRET_LENGTH is the shortest secret NewLabelSecret accepts.
+	// Secret hashes are public, so a secret must be too long to find by
+	// trying every one: 16 random characters of a 32 character alphabet
+	// hold 80 bits. Processors only see the hash and cannot check this.
+	MIN_SECRET_LENGTH int = 16
+)
+
+// LabelSecret commits a label to the secret under its scratch-off without
+// revealing it: Hash is SecretHash(Salt, secret). The zero value means the
+// label has no secret.
+type LabelSecret struct {
+	Salt string
+	Hash string
+}
+
+// Claim records who claimed a label and when they say they did.
+type Claim struct {
+	Claimant  string
+	ClaimedAt string
+}
+
+// SecretHash returns the hash a label stores for secret under salt.
+func SecretHash(salt string, secret string) string {
+	return Hexdigest(salt + secret)
+}
+
+// NewLabelSecret salts and hashes a secret with a random salt.
+func NewLabelSecret(secret string) (LabelSecret, error) {
+	if len(secret) < MIN_SECRET_LENGTH {
+		return LabelSecret{}, fmt.Errorf("Secret must be at least %d characters", MIN_SECRET_LENGTH)
+	}
+	salt := make([]byte, SALT_LENGTH/2)
+	if _, err := rand.Read(salt); err != nil {
+		return LabelSecret{}, err
+	}
+	saltHex := hex.EncodeToString(salt)
+	return LabelSecret{Salt: saltHex, Hash: SecretHash(saltHex, secret)}, nil
+}
+
+// IsSet reports whether the label has a secret.
+func (self LabelSecret) IsSet() bool {
+	return self != LabelSecret{}
+}
+
+// Check validates the form of a secret commitment.
+func (self LabelSecret) Check() error {
+	if len(self.Salt) < SALT_LENGTH || !isHex(self.Salt) {
+		return fmt.Errorf("Secret salt must be at least %d hex characters", SALT_LENGTH)
+	}
+	if len(self.Hash) != SECRET_HASH_LENGTH || !isHex(self.Hash) {
+		return fmt.Errorf("Secret hash must be %d hex characters", SECRET_HASH_LENGTH)
+	}
+	return nil
+}
+
+// Matches reports whether secret is the one the label was committed to.
+func (self LabelSecret) Matches(secret string) bool {
+	hash := SecretHash(self.Salt, secret)
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(self.Hash)) == 1
+}
+
+// isHex reports whether str is lower case hex, as Hexdigest writes it.
+func isHex(str string) bool {
+	for _, r := range str {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,76 @@
+package protocol
+
+import (
+	"strings"
+	"testing"
+)
+
+const secretSalt = "00112233445566778899aabbccddeeff"
+
+const scratchSecret = "K7QF-2MXP-9TBV-4HRW"
+
+func TestLabelSecret(t *testing.T) {
+	secret, err := NewLabelSecret(scratchSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := secret.Check(); err != nil {
+		t.Errorf("NewLabelSecret made an invalid secret: %v", err)
+	}
+	if !secret.Matches(scratchSecret) {
+		t.Error("Secret does not match the secret it was made from")
+	}
+	for _, guess := range []string{"", strings.ToLower(scratchSecret), scratchSecret + " ", scratchSecret[1:]} {
+		if secret.Matches(guess) {
+			t.Errorf("Secret matches %q", guess)
+		}
+	}
+	other, _ := NewLabelSecret(scratchSecret)
+	if other.Salt == secret.Salt || other.Hash == secret.Hash {
+		t.Error("NewLabelSecret reused a salt")
+	}
+	for _, short := range []string{"", "scratch", scratchSecret[:MIN_SECRET_LENGTH-1]} {
+		if _, err := NewLabelSecret(short); err == nil {
+			t.Errorf("NewLabelSecret accepted %q", short)
+		}
+	}
+}
+
+func TestLabelSecretCheck(t *testing.T) {
+	hash := SecretHash(secretSalt, "scratch")
+	invalid := map[string]LabelSecret{
+		"short salt":      {secretSalt[:SALT_LENGTH-2], hash},
+		"non-hex salt":    {strings.Repeat("g", SALT_LENGTH), hash},
+		"short hash":      {secretSalt, hash[:SECRET_HASH_LENGTH-2]},
+		"upper case hash": {secretSalt, strings.ToUpper(hash)},
+		"no hash":         {Salt: secretSalt},
+	}
+	for name, secret := range invalid {
+		if err := secret.Check(); err == nil {
+			t.Errorf("%s: Check accepted %+v", name, secret)
+		}
+	}
+	if _, err := NewLabelRecord(Payload{WineLabelID: "125", Lattitude: "0", Longitude: "0",
+		Secret: invalid["short hash"]}, "02ab"); err == nil {
+		t.Error("NewLabelRecord accepted an invalid secret")
+	}
+}
+
+func TestMintSecrets(t *testing.T) {
+	secret := LabelSecret{secretSalt, SecretHash(secretSalt, "scratch")}
+	mint := MintPayload{Prefix: "W-", First: 1, Count: 2, Digits: 4, Lattitude: "0", Longitude: "0",
+		Secrets: map[string]LabelSecret{"W-0002": secret}}
+	if _, err := mint.Labels(); err != nil {
+		t.Fatal(err)
+	}
+	for id, want := range map[string]LabelSecret{"W-0001": {}, "W-0002": secret} {
+		record, err := NewMintedRecord(mint, id, "02ab")
+		if err != nil || record.Secret != want {
+			t.Errorf("NewMintedRecord(%v) secret = %+v, %v, want %+v", id, record.Secret, err, want)
+		}
+	}
+	mint.Secrets["W-0003"] = secret
+	if _, err := mint.Labels(); err == nil {
+		t.Error("Labels accepted a secret for a label that is not minted")
+	}
+}
@@ -0,0 +1,162 @@
+package protocol
+
+import (
+	"fmt"
+)
+
+// Family versions. A 1.0 transaction carries a bare CBOR WineLabelPayload;
+// a 2.0 transaction wraps it in an Envelope naming its schema version, in
+// CBOR or, after PROTOBUF_MARKER, in protobuf.
+//
+// State is always written enveloped. Bare records in state were written by
+// 1.0 processors and are upgraded to the current schema when read.
+const (
+	FAMILY_VERSION_1 string = "1.0"
+	FAMILY_VERSION_2 string = "2.0"
+
+	// SCHEMA_VERSION is the version of the payloads and records this
+	// package writes. Bare, unenveloped data is schema version 1.
+	SCHEMA_VERSION uint64 = 2
+)
+
+// FAMILY_VERSIONS lists every family version a processor serves.
+var FAMILY_VERSIONS = []string{FAMILY_VERSION_1, FAMILY_VERSION_2}
+
+// Envelope marks CBOR encoded data with the schema version it was written
+// in, so that the schema can change without a chain reset.
+type Envelope struct {
+	Version uint64
+	Body    []byte
+}
+
+// EncodePayload encodes a transaction payload for the given family version.
+func EncodePayload(familyVersion string, payload WineLabelPayload) ([]byte, error) {
+	switch familyVersion {
+	case FAMILY_VERSION_1:
+		return EncodeCBOR(payload)
+	case FAMILY_VERSION_2:
+		return encodeEnvelope(payload)
+	}
+	return nil, fmt.Errorf("Unsupported family version: %v", familyVersion)
+}
+
+// DecodePayload decodes a transaction payload sent under the given family
+// version.
+func DecodePayload(familyVersion string, data []byte) (WineLabelPayload, error) {
+	var payload WineLabelPayload
+	if len(data) > MAX_PAYLOAD_SIZE {
+		return payload, fmt.Errorf("Payload of %d bytes exceeds the maximum of %d",
+			len(data), MAX_PAYLOAD_SIZE)
+	}
+	switch familyVersion {
+	case FAMILY_VERSION_1:
+		return payload, DecodeCBOR(data, &payload)
+	case FAMILY_VERSION_2:
+		if IsProtobuf(data) {
+			return decodeProtobufPayload(data)
+		}
+		body, _, err := unwrapEnvelope(data)
+		if err != nil {
+			return payload, err
+		}
+		return payload, DecodeCBOR(body, &payload)
+	}
+	return payload, fmt.Errorf("Unsupported family version: %v", familyVersion)
+}
+
+// EncodeRecord encodes a state record in the current schema version.
+func EncodeRecord(record interface{}) ([]byte, error) {
+	return encodeEnvelope(record)
+}
+
+// DecodeRecord decodes a state record written in any schema version and
+// either encoding into pointer.
+func DecodeRecord(data []byte, pointer interface{}) error {
+	if IsProtobuf(data) {
+		return decodeProtobufRecord(data, pointer)
+	}
+	body, _, err := unwrapEnvelope(data)
+	if err != nil {
+		return err
+	}
+	return DecodeCBOR(body, pointer)
+}
+
+// DecodeLabelRecord decodes the state stored at a label address, upgrading
+// records written by 1.0 processors: those predate the label lifecycle, and
+// the oldest stored the raw Payload with its coordinates as strings, and
+// swapped, since it built that Payload positionally. Upgraded records have
+// no owner; the handler lets the family admins act for the owner until one
+// of them hands the label over.
+func DecodeLabelRecord(data []byte) (LabelRecord, error) {
+	var record LabelRecord
+	if IsProtobuf(data) {
+		return record, decodeProtobufRecord(data, &record)
+	}
+	body, version, err := unwrapEnvelope(data)
+	if err != nil {
+		return record, err
+	}
+	if version > 1 {
+		return record, DecodeCBOR(body, &record)
+	}
+	var legacy Payload
+	if DecodeCBOR(body, &legacy) == nil {
+		record = LabelRecord{
+			WineLabelID: legacy.WineLabelID,
+			PrintedAt:   legacy.PrintedAt,
+			Status:      STATUS_PRINTED,
+		}
+		if legacy.Lattitude != "" {
+			position, err := ParseGeoPoint(legacy.Longitude, legacy.Lattitude)
+			if err != nil {
+				return record, fmt.Errorf("Cannot upgrade label %v: %v", record.WineLabelID, err)
+			}
+			record.Position = position
+		}
+		return record, nil
+	}
+	if err := DecodeCBOR(body, &record); err != nil {
+		return record, err
+	}
+	if record.Status == "" {
+		record.Status = STATUS_PRINTED
+	}
+	return record, nil
+}
+
+func encodeEnvelope(value interface{}) ([]byte, error) {
+	body, err := EncodeCBOR(value)
+	if err != nil {
+		return nil, err
+	}
+	return EncodeCBOR(Envelope{Version: SCHEMA_VERSION, Body: body})
+}
+
+// unwrapEnvelope returns the body of enveloped data and its schema
+// version. Data without an envelope is returned whole as version 1.
+func unwrapEnvelope(data []byte) ([]byte, uint64, error) {
+	var probe struct{ Version uint64 }
+	if err := decodeCBORWith(envelopeProbe, data, &probe); err != nil {
+		return nil, 0, err
+	}
+	if probe.Version == 0 {
+		return data, 1, nil
+	}
+	var envelope Envelope
+	if err := DecodeCBOR(data, &envelope); err != nil {
+		return nil, 0, err
+	}
+	if err := checkSchemaVersion(envelope.Version); err != nil {
+		return nil, 0, err
+	}
+	return envelope.Body, envelope.Version, nil
+}
+
+func checkSchemaVersion(version uint64) error {
+	if version > SCHEMA_VERSION {
+		return fmt.Errorf("Unsupported schema version %d, newest known is %d",
+			version, SCHEMA_VERSION)
+	}
+	return nil
+}
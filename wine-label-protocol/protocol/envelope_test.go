@@ -0,0 +1,102 @@
+package protocol
+
+import (
+	"encoding/hex"
+	"reflect"
+	"testing"
+)
+
+func TestPayloadFamilyVersions(t *testing.T) {
+	payload := WineLabelPayload{Payload: Payload{WineLabelID: "125", PrintedAt: "loc", Longitude: "34.3", Lattitude: "23.2"}, Verb: VERB_SET}
+	for _, version := range FAMILY_VERSIONS {
+		data, err := EncodePayload(version, payload)
+		if err != nil {
+			t.Fatalf("EncodePayload(%v): %v", version, err)
+		}
+		decoded, err := DecodePayload(version, data)
+		if err != nil {
+			t.Fatalf("DecodePayload(%v): %v", version, err)
+		}
+		if decoded.Payload != payload.Payload || decoded.Verb != payload.Verb {
+			t.Errorf("DecodePayload(%v) = %+v, want %+v", version, decoded, payload)
+		}
+	}
+	if _, err := EncodePayload("3.0", payload); err == nil {
+		t.Error("EncodePayload accepted family version 3.0")
+	}
+}
+
+func TestEnvelopeGolden(t *testing.T) {
+	data, err := EncodePayload(FAMILY_VERSION_2,
+		WineLabelPayload{Payload: Payload{WineLabelID: "125"}, Verb: VERB_DELETE})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "a264426f64795901baa9644d696e74ad634c6f746065436f756e740065466972737400655072696e74a46454696d6560675072696e7465726068466163696c69747960684f70657261746f726066446967697473006650726566697860675072696e746572606753656372657473f668466163696c69747960684c6162656c494473f6694c617474697475646560694c6f6e67697475646560695072696e74656441746064566572626664656c65746566526561736f6e60675061796c6f6164a7634c6f7460655072696e74a46454696d6560675072696e7465726068466163696c69747960684f70657261746f726066536563726574a26448617368606453616c7460694c617474697475646560694c6f6e67697475646560695072696e7465644174606b57696e654c6162656c49446331323569436c61696d656441746069526563697069656e74606a4c6f7444657461696c73a5654c6f744944606756696e746167650069566172696574616c73f66b417070656c6c6174696f6e606b426f74746c65436f756e74006c4f7267616e69736174696f6ea4644b657973f6644e616d6560645479706560654f72674944606e52657665616c6564536563726574606756657273696f6e02"
+	if hex.EncodeToString(data) != want {
+		t.Errorf("EncodePayload = %x, want %s", data, want)
+	}
+}
+
+// The records the original processor stored for label 125, printed in
+// Bordeaux at 44.837789,-0.57918, for label 126, printed in Sydney at
+// -33.8688,151.2093, and for a label it deleted. It stored each label's
+// latitude as its longitude and the other way round.
+const (
+	legacyBordeaux = "a46b57696e654c6162656c494463313235695072696e746564417468426f726465617578694c6f6e6769747564656934342e383337373839694c6174746974756465682d302e3537393138"
+	legacySydney   = "a46b57696e654c6162656c494463313236695072696e7465644174665379646e6579694c6f6e676974756465682d33332e38363838694c6174746974756465683135312e32303933"
+	legacyDeleted  = "a46b57696e654c6162656c494460695072696e746564417460694c6f6e67697475646560694c617474697475646560"
+)
+
+func TestDecodeLabelRecordUpgrades(t *testing.T) {
+	bordeaux := LabelRecord{
+		WineLabelID: "125",
+		PrintedAt:   "Bordeaux",
+		Position:    GeoPoint{Latitude: 44837789, Longitude: -579180},
+		Status:      STATUS_PRINTED,
+	}
+	sydney := LabelRecord{
+		WineLabelID: "126",
+		PrintedAt:   "Sydney",
+		Position:    GeoPoint{Latitude: -33868800, Longitude: 151209300},
+		Status:      STATUS_PRINTED,
+	}
+	bareRecord, _ := EncodeCBOR(LabelRecord{WineLabelID: "125", PrintedAt: "Bordeaux", Position: bordeaux.Position})
+	current, _ := EncodeRecord(bordeaux)
+	cases := []struct {
+		name string
+		data []byte
+		want LabelRecord
+	}{
+		{"1.0 payload record", mustHex(t, legacyBordeaux), bordeaux},
+		{"1.0 payload record beyond 90 degrees east", mustHex(t, legacySydney), sydney},
+		{"1.0 deleted record", mustHex(t, legacyDeleted), LabelRecord{Status: STATUS_PRINTED}},
+		{"1.0 label record", bareRecord, bordeaux},
+		{"current record", current, bordeaux},
+	}
+	for _, c := range cases {
+		record, err := DecodeLabelRecord(c.data)
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+		} else if !reflect.DeepEqual(record, c.want) {
+			t.Errorf("%s: DecodeLabelRecord = %+v, want %+v", c.name, record, c.want)
+		}
+	}
+}
+
+func TestDecodeRecordRejectsNewerSchema(t *testing.T) {
+	data, _ := EncodeCBOR(Envelope{Version: SCHEMA_VERSION + 1, Body: []byte{0xa0}})
+	var record LabelRecord
+	if err := DecodeRecord(data, &record); err == nil {
+		t.Error("DecodeRecord accepted a newer schema version")
+	}
+}
+
+func mustHex(t *testing.T, str string) []byte {
+	t.Helper()
+	data, err := hex.DecodeString(str)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
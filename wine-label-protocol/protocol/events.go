@@ -0,0 +1,33 @@
+package protocol
+
+// Event types the wine-label handler emits when a label changes. Every
+// event carries the ATTRIBUTE_* attributes and the resulting LabelRecord,
+// encoded by EncodeRecord, as its data; for EVENT_DELETED that is the
+// record as it was before it was withdrawn. EVENT_SUSPICIOUS follows the
+// EVENT_SCANNED of the scan that flags a label, and EVENT_RECALLED_SCAN
+// that of any scan of a label in a recalled lot, and EVENT_CLAIM_REJECTED
+// the EVENT_UPDATED of a claim that did not claim the label; all three
+// also carry ATTRIBUTE_REASON.
+//
+// EVENT_LOT_RECALLED announces a recall. It carries ATTRIBUTE_LOT_ID in
+// place of ATTRIBUTE_LABEL_ID, ATTRIBUTE_REASON, and the recalled Lot as
+// its data.
+const (
+	EVENT_CREATED     string = "wine-label/created"
+	EVENT_UPDATED     string = "wine-label/updated"
+	EVENT_TRANSFERRED string = "wine-label/transferred"
+	EVENT_DELETED     string = "wine-label/deleted"
+	EVENT_SCANNED     string = "wine-label/scanned"
+	EVENT_SUSPICIOUS  string = "wine-label/suspicious"
+	EVENT_CLAIMED     string = "wine-label/claimed"
+
+	EVENT_RECALLED_SCAN  string = "wine-label/recalled-scan"
+	EVENT_CLAIM_REJECTED string = "wine-label/claim-rejected"
+	EVENT_LOT_RECALLED   string = "wine-label/lot-recalled"
+
+	ATTRIBUTE_LABEL_ID string = "label_id"
+	ATTRIBUTE_SIGNER   string = "signer"
+	ATTRIBUTE_ADDRESS  string = "address"
+	ATTRIBUTE_REASON   string = "reason"
+	ATTRIBUTE_LOT_ID   string = "lot_id"
+)
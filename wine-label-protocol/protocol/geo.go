@@ -0,0 +1,143 @@
+package protocol
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Coordinates are WGS84 degrees held as fixed-precision integers of
+// microdegrees, so every processor stores the same value for the same
+// input and no float rounding reaches state.
+const (
+	MICRODEGREES_PER_DEGREE int64 = 1000000
+	COORDINATE_PRECISION    int   = 6
+
+	MAX_LATITUDE  Latitude  = 90 * Latitude(MICRODEGREES_PER_DEGREE)
+	MIN_LATITUDE  Latitude  = -MAX_LATITUDE
+	MAX_LONGITUDE Longitude = 180 * Longitude(MICRODEGREES_PER_DEGREE)
+	MIN_LONGITUDE Longitude = -MAX_LONGITUDE
+)
+
+// Latitude and Longitude are distinct types so that one cannot be passed
+// where the other is expected.
+type Latitude int64
+type Longitude int64
+
+// GeoPoint is a validated WGS84 position.
+type GeoPoint struct {
+	Latitude  Latitude
+	Longitude Longitude
+}
+
+func ParseLatitude(str string) (Latitude, error) {
+	value, err := parseMicrodegrees(str)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid latitude %q: %v", str, err)
+	}
+	lat := Latitude(value)
+	if lat < MIN_LATITUDE || lat > MAX_LATITUDE {
+		return 0, fmt.Errorf("Invalid latitude %q: must be between -90 and 90", str)
+	}
+	return lat, nil
+}
+
+func ParseLongitude(str string) (Longitude, error) {
+	value, err := parseMicrodegrees(str)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid longitude %q: %v", str, err)
+	}
+	long := Longitude(value)
+	if long < MIN_LONGITUDE || long > MAX_LONGITUDE {
+		return 0, fmt.Errorf("Invalid longitude %q: must be between -180 and 180", str)
+	}
+	return long, nil
+}
+
+func ParseGeoPoint(lat, long string) (GeoPoint, error) {
+	latitude, err := ParseLatitude(lat)
+	if err != nil {
+		return GeoPoint{}, err
+	}
+	longitude, err := ParseLongitude(long)
+	if err != nil {
+		return GeoPoint{}, err
+	}
+	return GeoPoint{Latitude: latitude, Longitude: longitude}, nil
+}
+
+func (lat Latitude) Degrees() float64 {
+	return float64(lat) / float64(MICRODEGREES_PER_DEGREE)
+}
+
+func (lat Latitude) String() string {
+	return formatMicrodegrees(int64(lat))
+}
+
+func (long Longitude) Degrees() float64 {
+	return float64(long) / float64(MICRODEGREES_PER_DEGREE)
+}
+
+func (long Longitude) String() string {
+	return formatMicrodegrees(int64(long))
+}
+
+func (point GeoPoint) String() string {
+	return fmt.Sprintf("%v,%v", point.Latitude, point.Longitude)
+}
+
+// parseMicrodegrees parses a decimal degree string such as "-33.8688"
+// without going through float64. Digits beyond COORDINATE_PRECISION are
+// truncated.
+func parseMicrodegrees(str string) (int64, error) {
+	str = strings.TrimSpace(str)
+	negative := strings.HasPrefix(str, "-")
+	if negative || strings.HasPrefix(str, "+") {
+		str = str[1:]
+	}
+
+	parts := strings.SplitN(str, ".", 2)
+	whole := parts[0]
+	fraction := ""
+	if len(parts) == 2 {
+		fraction = parts[1]
+	}
+	if whole == "" && fraction == "" {
+		return 0, fmt.Errorf("not a number")
+	}
+	if !isDigits(whole) || !isDigits(fraction) || len(whole) > 3 {
+		return 0, fmt.Errorf("not a decimal number of degrees")
+	}
+	if len(fraction) > COORDINATE_PRECISION {
+		fraction = fraction[:COORDINATE_PRECISION]
+	}
+	fraction += strings.Repeat("0", COORDINATE_PRECISION-len(fraction))
+
+	value, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if negative {
+		value = -value
+	}
+	return value, nil
+}
+
+func formatMicrodegrees(value int64) string {
+	sign := ""
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+	return fmt.Sprintf("%s%d.%06d", sign,
+		value/MICRODEGREES_PER_DEGREE, value%MICRODEGREES_PER_DEGREE)
+}
+
+func isDigits(str string) bool {
+	for _, r := range str {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,62 @@
+package protocol
+
+import "testing"
+
+func TestParseGeoPoint(t *testing.T) {
+	cases := []struct {
+		lat, long string
+		want      GeoPoint
+		ok        bool
+	}{
+		{"44.837789", "-0.57918", GeoPoint{44837789, -579180}, true},
+		{"-33.8688", "151.2093", GeoPoint{-33868800, 151209300}, true},
+		{"90", "180", GeoPoint{MAX_LATITUDE, MAX_LONGITUDE}, true},
+		{"-90", "-180", GeoPoint{MIN_LATITUDE, MIN_LONGITUDE}, true},
+		{"+.5", "1.", GeoPoint{500000, 1000000}, true},
+		{"12.34567891", "0", GeoPoint{12345678, 0}, true},
+		{"90.000001", "0", GeoPoint{}, false},
+		{"0", "-180.5", GeoPoint{}, false},
+		{"", "0", GeoPoint{}, false},
+		{"-", "0", GeoPoint{}, false},
+		{"-+5", "0", GeoPoint{}, false},
+		{"0", "+-5", GeoPoint{}, false},
+		{"--5", "0", GeoPoint{}, false},
+		{"1e2", "0", GeoPoint{}, false},
+		{"12,5", "0", GeoPoint{}, false},
+		{"0", "NaN", GeoPoint{}, false},
+		{"0", "1000", GeoPoint{}, false},
+	}
+	for _, c := range cases {
+		got, err := ParseGeoPoint(c.lat, c.long)
+		if c.ok && err != nil {
+			t.Errorf("ParseGeoPoint(%q, %q): %v", c.lat, c.long, err)
+		} else if !c.ok && err == nil {
+			t.Errorf("ParseGeoPoint(%q, %q) = %v, want error", c.lat, c.long, got)
+		} else if got != c.want {
+			t.Errorf("ParseGeoPoint(%q, %q) = %v, want %v", c.lat, c.long, got, c.want)
+		}
+	}
+}
+
+func TestGeoPointString(t *testing.T) {
+	point := GeoPoint{Latitude: -33868800, Longitude: 151209300}
+	if s := point.String(); s != "-33.868800,151.209300" {
+		t.Errorf("String() = %q", s)
+	}
+}
+
+func TestNewLabelRecordKeepsAxes(t *testing.T) {
+	record, err := NewLabelRecord(Payload{
+		WineLabelID: "125",
+		PrintedAt:   "loc",
+		Longitude:   "151.2093",
+		Lattitude:   "-33.8688",
+	}, "owner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := GeoPoint{Latitude: -33868800, Longitude: 151209300}
+	if record.Position != want {
+		t.Errorf("Position = %v, want %v", record.Position, want)
+	}
+}
@@ -0,0 +1,291 @@
+package protocol
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"strings"
+)
+
+// The spatial index files each label under the geohash of its position,
+// written in hex rather than base32: every hex digit holds two longitude
+// and two latitude bits, so each digit of an address prefix narrows the
+// region four times along both axes. A region is listed by querying the
+// prefixes of the cells that cover it.
+const (
+	// GEOHASH_DIGITS cells are about 40 by 20 metres at the equator.
+	GEOHASH_DIGITS int = 10
+	// MAX_GEO_PREFIXES bounds how many prefixes a region query uses; larger
+	// regions are covered by coarser cells.
+	MAX_GEO_PREFIXES int = 16
+
+	EARTH_RADIUS_KM       float64 = 6371.0088
+	EARTH_RADIUS_WHOLE_KM uint64  = 6371
+	// TRIG_SCALE is the fixed-point scale of sines and cosines in integer
+	// distance computations.
+	TRIG_SCALE int64 = 1000000000
+)
+
+// BoundingBox is the region between two corners, inclusive. Boxes that
+// cross the antimeridian are not supported.
+type BoundingBox struct {
+	Min GeoPoint
+	Max GeoPoint
+}
+
+func (self BoundingBox) Contains(point GeoPoint) bool {
+	return point.Latitude >= self.Min.Latitude && point.Latitude <= self.Max.Latitude &&
+		point.Longitude >= self.Min.Longitude && point.Longitude <= self.Max.Longitude
+}
+
+// Check validates that the corners are in range and south-west and
+// north-east of each other.
+func (self BoundingBox) Check() error {
+	for _, corner := range []GeoPoint{self.Min, self.Max} {
+		if corner.Latitude < MIN_LATITUDE || corner.Latitude > MAX_LATITUDE ||
+			corner.Longitude < MIN_LONGITUDE || corner.Longitude > MAX_LONGITUDE {
+			return fmt.Errorf("Bounding box corner %v is out of range", corner)
+		}
+	}
+	if self.Min.Latitude > self.Max.Latitude || self.Min.Longitude > self.Max.Longitude {
+		return fmt.Errorf("Bounding box corners %v and %v are not south-west and north-east",
+			self.Min, self.Max)
+	}
+	return nil
+}
+
+// ParseBoundingBox parses a box written as "south,west,north,east" in
+// degrees.
+func ParseBoundingBox(str string) (BoundingBox, error) {
+	fields := strings.Split(str, ",")
+	if len(fields) != 4 {
+		return BoundingBox{}, fmt.Errorf("Bounding box %q must be south,west,north,east", str)
+	}
+	min, err := ParseGeoPoint(strings.TrimSpace(fields[0]), strings.TrimSpace(fields[1]))
+	if err != nil {
+		return BoundingBox{}, err
+	}
+	max, err := ParseGeoPoint(strings.TrimSpace(fields[2]), strings.TrimSpace(fields[3]))
+	if err != nil {
+		return BoundingBox{}, err
+	}
+	box := BoundingBox{Min: min, Max: max}
+	return box, box.Check()
+}
+
+// GeoCell returns the hex geohash of the cell of the given number of
+// digits that contains point.
+func GeoCell(point GeoPoint, digits int) string {
+	return interleave(lonCell(point.Longitude, digits), latCell(point.Latitude, digits), digits)
+}
+
+// CoveringCells returns the geohash prefixes of the finest cells that
+// cover box without needing more than MAX_GEO_PREFIXES of them.
+func CoveringCells(box BoundingBox) ([]string, error) {
+	if err := box.Check(); err != nil {
+		return nil, err
+	}
+	digits := GEOHASH_DIGITS
+	west, east, south, north := boxCells(box, digits)
+	// A single digit splits the world into 4 by 4 cells, so the loop always
+	// ends within MAX_GEO_PREFIXES.
+	for (east-west+1)*(north-south+1) > uint64(MAX_GEO_PREFIXES) {
+		digits--
+		west, east, south, north = boxCells(box, digits)
+	}
+	cells := make([]string, 0, (east-west+1)*(north-south+1))
+	for lon := west; lon <= east; lon++ {
+		for lat := south; lat <= north; lat++ {
+			cells = append(cells, interleave(lon, lat, digits))
+		}
+	}
+	return cells, nil
+}
+
+// RadiusBoxes returns bounding boxes that together contain every point
+// within km of center. A circle crossing the antimeridian is covered by a
+// box on either side of it.
+func RadiusBoxes(center GeoPoint, km float64) []BoundingBox {
+	latDelta := km / EARTH_RADIUS_KM * 180 / math.Pi
+	lonDelta := 180.0
+	if cos := math.Cos(center.Latitude.Degrees() * math.Pi / 180); cos > 0 {
+		lonDelta = math.Min(180, latDelta/cos)
+	}
+	minLat := math.Max(center.Latitude.Degrees()-latDelta, -90)
+	maxLat := math.Min(center.Latitude.Degrees()+latDelta, 90)
+	box := func(minLon float64, maxLon float64) BoundingBox {
+		return BoundingBox{
+			Min: GeoPoint{Latitude: latitudeOf(minLat), Longitude: longitudeOf(minLon)},
+			Max: GeoPoint{Latitude: latitudeOf(maxLat), Longitude: longitudeOf(maxLon)},
+		}
+	}
+	if maxLat >= 90 || minLat <= -90 || lonDelta >= 180 {
+		return []BoundingBox{box(-180, 180)}
+	}
+	minLon := center.Longitude.Degrees() - lonDelta
+	maxLon := center.Longitude.Degrees() + lonDelta
+	switch {
+	case minLon < -180:
+		return []BoundingBox{box(minLon+360, 180), box(-180, maxLon)}
+	case maxLon > 180:
+		return []BoundingBox{box(minLon, 180), box(-180, maxLon-360)}
+	}
+	return []BoundingBox{box(minLon, maxLon)}
+}
+
+// DistanceKm returns the great circle distance between two points.
+func DistanceKm(a GeoPoint, b GeoPoint) float64 {
+	lat1 := a.Latitude.Degrees() * math.Pi / 180
+	lat2 := b.Latitude.Degrees() * math.Pi / 180
+	dLat := lat2 - lat1
+	dLon := (b.Longitude.Degrees() - a.Longitude.Degrees()) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * EARTH_RADIUS_KM * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// ChordKm returns the straight-line distance between two points through
+// the earth, in whole kilometres. It is computed in integers, so that
+// every processor gets the same result, and never exceeds the great
+// circle distance, by less than 1% up to 1500 km.
+func ChordKm(a GeoPoint, b GeoPoint) uint64 {
+	va, vb := unitVector(a), unitVector(b)
+	var squared uint64
+	for i := range va {
+		d := va[i] - vb[i]
+		squared += uint64(d * d)
+	}
+	return isqrt(squared) * EARTH_RADIUS_WHOLE_KM / uint64(TRIG_SCALE)
+}
+
+// GeoIndexPrefix returns the address prefix of every label indexed in a
+// geohash cell.
+func (ns Namespace) GeoIndexPrefix(cell string) string {
+	return ns.SpacePrefix(GEO_INDEX_SPACE) + cell
+}
+
+// GeoIndexAddress returns the address of a label's spatial index entry.
+func (ns Namespace) GeoIndexAddress(point GeoPoint, labelID string) string {
+	keyLength := ADDRESS_LENGTH - NAMESPACE_PREFIX_LENGTH - SPACE_LENGTH - GEOHASH_DIGITS
+	return ns.GeoIndexPrefix(GeoCell(point, GEOHASH_DIGITS)) + Hexdigest(labelID)[:keyLength]
+}
+
+func boxCells(box BoundingBox, digits int) (uint64, uint64, uint64, uint64) {
+	return lonCell(box.Min.Longitude, digits), lonCell(box.Max.Longitude, digits),
+		latCell(box.Min.Latitude, digits), latCell(box.Max.Latitude, digits)
+}
+
+// lonCell and latCell number the cells along one axis at a geohash
+// precision; each digit halves the cells twice.
+func lonCell(lon Longitude, digits int) uint64 {
+	return axisCell(int64(lon-MIN_LONGITUDE), int64(MAX_LONGITUDE-MIN_LONGITUDE), digits)
+}
+
+func latCell(lat Latitude, digits int) uint64 {
+	return axisCell(int64(lat-MIN_LATITUDE), int64(MAX_LATITUDE-MIN_LATITUDE), digits)
+}
+
+func axisCell(offset int64, span int64, digits int) uint64 {
+	cells := uint64(1) << uint(2*digits)
+	cell := uint64(offset) * cells / uint64(span)
+	if cell >= cells {
+		cell = cells - 1
+	}
+	return cell
+}
+
+// interleave writes the bits of a longitude and latitude cell alternately,
+// longitude first as in a geohash, as hex.
+func interleave(lon uint64, lat uint64, digits int) string {
+	var hex strings.Builder
+	for digit := digits - 1; digit >= 0; digit-- {
+		lonBits := (lon >> uint(2*digit)) & 3
+		latBits := (lat >> uint(2*digit)) & 3
+		value := (lonBits>>1)<<3 | (latBits>>1)<<2 | (lonBits&1)<<1 | latBits&1
+		fmt.Fprintf(&hex, "%x", value)
+	}
+	return hex.String()
+}
+
+func latitudeOf(degrees float64) Latitude {
+	return Latitude(math.Round(degrees * float64(MICRODEGREES_PER_DEGREE)))
+}
+
+func longitudeOf(degrees float64) Longitude {
+	return Longitude(math.Round(degrees * float64(MICRODEGREES_PER_DEGREE)))
+}
+
+// unitVector returns the point on the unit sphere, scaled by TRIG_SCALE.
+func unitVector(point GeoPoint) [3]int64 {
+	lat, lon := int64(point.Latitude), int64(point.Longitude)
+	cosLat := cosMicrodegrees(lat)
+	return [3]int64{
+		cosLat * cosMicrodegrees(lon) / TRIG_SCALE,
+		cosLat * sinMicrodegrees(lon) / TRIG_SCALE,
+		sinMicrodegrees(lat),
+	}
+}
+
+// sinMicrodegrees returns the sine of an angle in microdegrees, scaled by
+// TRIG_SCALE, interpolating sinTable linearly between whole degrees.
+func sinMicrodegrees(angle int64) int64 {
+	const degree, halfTurn = MICRODEGREES_PER_DEGREE, 180 * MICRODEGREES_PER_DEGREE
+	angle %= 2 * halfTurn
+	if angle < 0 {
+		angle += 2 * halfTurn
+	}
+	sign := int64(1)
+	if angle >= halfTurn {
+		angle -= halfTurn
+		sign = -1
+	}
+	if angle > halfTurn/2 {
+		angle = halfTurn - angle
+	}
+	whole, fraction := angle/degree, angle%degree
+	value := sinTable[whole]
+	if fraction > 0 {
+		value += (sinTable[whole+1] - sinTable[whole]) * fraction / degree
+	}
+	return sign * value
+}
+
+func cosMicrodegrees(angle int64) int64 {
+	return sinMicrodegrees(angle + 90*MICRODEGREES_PER_DEGREE)
+}
+
+// isqrt returns the integer square root of n, rounded down.
+func isqrt(n uint64) uint64 {
+	if n < 2 {
+		return n
+	}
+	x := uint64(1) << ((bits.Len64(n) + 1) / 2)
+	for {
+		y := (x + n/x) / 2
+		if y >= x {
+			return x
+		}
+		x = y
+	}
+}
+
+// sinTable holds the sine of each whole degree from 0 to 90, scaled by
+// TRIG_SCALE.
+var sinTable = [91]int64{
+	0, 17452406, 34899497, 52335956, 69756474, 87155743,
+	104528463, 121869343, 139173101, 156434465, 173648178, 190808995,
+	207911691, 224951054, 241921896, 258819045, 275637356, 292371705,
+	309016994, 325568154, 342020143, 358367950, 374606593, 390731128,
+	406736643, 422618262, 438371147, 453990500, 469471563, 484809620,
+	500000000, 515038075, 529919264, 544639035, 559192903, 573576436,
+	587785252, 601815023, 615661475, 629320391, 642787610, 656059029,
+	669130606, 681998360, 694658370, 707106781, 719339800, 731353702,
+	743144825, 754709580, 766044443, 777145961, 788010754, 798635510,
+	809016994, 819152044, 829037573, 838670568, 848048096, 857167301,
+	866025404, 874619707, 882947593, 891006524, 898794046, 906307787,
+	913545458, 920504853, 927183855, 933580426, 939692621, 945518576,
+	951056516, 956304756, 961261696, 965925826, 970295726, 974370065,
+	978147601, 981627183, 984807753, 987688341, 990268069, 992546152,
+	994521895, 996194698, 997564050, 998629535, 999390827, 999847695,
+	1000000000,
+}
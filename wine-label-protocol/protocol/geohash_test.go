@@ -0,0 +1,199 @@
+package protocol
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func mustGeoPoint(t *testing.T, lat, long string) GeoPoint {
+	t.Helper()
+	point, err := ParseGeoPoint(lat, long)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return point
+}
+
+func TestGeoCell(t *testing.T) {
+	// The base32 geohash of this point is u4pruydqqvj; its first 24 bits
+	// are d12b7d in hex.
+	point := mustGeoPoint(t, "57.64911", "10.40744")
+	if cell := GeoCell(point, 6); cell != "d12b7d" {
+		t.Errorf("GeoCell = %s, want d12b7d", cell)
+	}
+	if cell := GeoCell(point, GEOHASH_DIGITS); !strings.HasPrefix(cell, "d12b7d") || len(cell) != GEOHASH_DIGITS {
+		t.Errorf("GeoCell = %s, want %d digits under d12b7d", cell, GEOHASH_DIGITS)
+	}
+	corners := map[string]GeoPoint{
+		"0": {Latitude: MIN_LATITUDE, Longitude: MIN_LONGITUDE},
+		"f": {Latitude: MAX_LATITUDE, Longitude: MAX_LONGITUDE},
+		"c": {},
+	}
+	for want, corner := range corners {
+		if cell := GeoCell(corner, 1); cell != want {
+			t.Errorf("GeoCell(%v) = %s, want %s", corner, cell, want)
+		}
+	}
+}
+
+func TestGeoIndexAddress(t *testing.T) {
+	ns := NewNamespace(FAMILY_NAME)
+	point := mustGeoPoint(t, "57.64911", "10.40744")
+	address := ns.GeoIndexAddress(point, "125")
+	if len(address) != ADDRESS_LENGTH {
+		t.Errorf("GeoIndexAddress has length %d, want %d", len(address), ADDRESS_LENGTH)
+	}
+	if !strings.HasPrefix(address, ns.GeoIndexPrefix("d12b7d")) {
+		t.Errorf("GeoIndexAddress %s is not in its cell", address)
+	}
+}
+
+func TestCoveringCells(t *testing.T) {
+	boxes := []BoundingBox{
+		{Min: mustGeoPoint(t, "-33.9", "18.4"), Max: mustGeoPoint(t, "-33.8", "18.5")},
+		{Min: mustGeoPoint(t, "44.8", "-0.6"), Max: mustGeoPoint(t, "44.8", "-0.6")},
+		{Min: mustGeoPoint(t, "-10", "-170"), Max: mustGeoPoint(t, "60", "170")},
+		{Min: mustGeoPoint(t, "-90", "-180"), Max: mustGeoPoint(t, "90", "180")},
+	}
+	for _, box := range boxes {
+		cells, err := CoveringCells(box)
+		if err != nil {
+			t.Fatalf("CoveringCells(%v): %v", box, err)
+		}
+		if len(cells) == 0 || len(cells) > MAX_GEO_PREFIXES {
+			t.Errorf("CoveringCells(%v) returned %d cells", box, len(cells))
+		}
+		for i := 0; i <= 10; i++ {
+			for j := 0; j <= 10; j++ {
+				point := GeoPoint{
+					Latitude:  box.Min.Latitude + (box.Max.Latitude-box.Min.Latitude)*Latitude(i)/10,
+					Longitude: box.Min.Longitude + (box.Max.Longitude-box.Min.Longitude)*Longitude(j)/10,
+				}
+				if !coveredBy(GeoCell(point, GEOHASH_DIGITS), cells) {
+					t.Errorf("CoveringCells(%v) = %v misses %v", box, cells, point)
+				}
+			}
+		}
+	}
+}
+
+func coveredBy(cell string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(cell, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCoveringCellsInvalid(t *testing.T) {
+	boxes := []BoundingBox{
+		{Min: mustGeoPoint(t, "10", "0"), Max: mustGeoPoint(t, "0", "10")},
+		{Min: mustGeoPoint(t, "0", "170"), Max: mustGeoPoint(t, "10", "-170")},
+		{Max: GeoPoint{Latitude: MAX_LATITUDE + 1}},
+	}
+	for _, box := range boxes {
+		if _, err := CoveringCells(box); err == nil {
+			t.Errorf("CoveringCells(%v) accepted an invalid box", box)
+		}
+	}
+}
+
+func TestParseBoundingBox(t *testing.T) {
+	box, err := ParseBoundingBox("44.5, -1.0, 45.2,0.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := BoundingBox{Min: mustGeoPoint(t, "44.5", "-1.0"), Max: mustGeoPoint(t, "45.2", "0.5")}
+	if box != want {
+		t.Errorf("ParseBoundingBox = %v, want %v", box, want)
+	}
+	for _, str := range []string{"", "44.5,-1.0,45.2", "45.2,-1.0,44.5,0.5", "44.5,west,45.2,0.5"} {
+		if _, err := ParseBoundingBox(str); err == nil {
+			t.Errorf("ParseBoundingBox(%q) accepted an invalid box", str)
+		}
+	}
+}
+
+func TestDistanceKm(t *testing.T) {
+	paris := mustGeoPoint(t, "48.8566", "2.3522")
+	london := mustGeoPoint(t, "51.5074", "-0.1278")
+	if d := DistanceKm(paris, london); math.Abs(d-343.5) > 1 {
+		t.Errorf("DistanceKm(paris, london) = %.1f, want about 343.5", d)
+	}
+	if d := DistanceKm(paris, paris); d != 0 {
+		t.Errorf("DistanceKm(paris, paris) = %v, want 0", d)
+	}
+}
+
+func TestChordKm(t *testing.T) {
+	paris := mustGeoPoint(t, "48.8566", "2.3522")
+	london := mustGeoPoint(t, "51.5074", "-0.1278")
+	shanghai := mustGeoPoint(t, "31.2304", "121.4737")
+	cases := []struct {
+		a, b GeoPoint
+		km   uint64
+	}{
+		{paris, paris, 0},
+		{paris, london, 343},
+		{paris, shanghai, 8467},
+		{mustGeoPoint(t, "0", "0"), mustGeoPoint(t, "0", "180"), 12742},
+		{mustGeoPoint(t, "-90", "0"), mustGeoPoint(t, "90", "0"), 12742},
+	}
+	for _, c := range cases {
+		km := ChordKm(c.a, c.b)
+		if km != c.km {
+			t.Errorf("ChordKm(%v, %v) = %d, want %d", c.a, c.b, km, c.km)
+		}
+		if float64(km) > DistanceKm(c.a, c.b) {
+			t.Errorf("ChordKm(%v, %v) = %d exceeds the great circle distance %.1f", c.a, c.b, km, DistanceKm(c.a, c.b))
+		}
+	}
+}
+
+func TestRadiusBoxes(t *testing.T) {
+	cases := []struct {
+		center GeoPoint
+		boxes  int
+	}{
+		{mustGeoPoint(t, "48.8566", "2.3522"), 1},
+		{mustGeoPoint(t, "-33.9", "18.4"), 1},
+		{mustGeoPoint(t, "89.9", "0"), 1},
+		{mustGeoPoint(t, "-17.7", "179.9"), 2},
+		{mustGeoPoint(t, "65", "-179.8"), 2},
+	}
+	for _, c := range cases {
+		boxes := RadiusBoxes(c.center, 50)
+		if len(boxes) != c.boxes {
+			t.Errorf("RadiusBoxes(%v, 50) = %v, want %d boxes", c.center, boxes, c.boxes)
+		}
+		// Points 49 km away in each direction fall inside one of the boxes.
+		for bearing := 0.0; bearing < 360; bearing += 15 {
+			point := destination(c.center, 49, bearing)
+			inside := false
+			for _, box := range boxes {
+				if err := box.Check(); err != nil {
+					t.Fatalf("RadiusBoxes(%v, 50): %v", c.center, err)
+				}
+				inside = inside || box.Contains(point)
+			}
+			if !inside {
+				t.Errorf("RadiusBoxes(%v, 50) = %v misses %v", c.center, boxes, point)
+			}
+		}
+	}
+}
+
+func destination(start GeoPoint, km float64, bearing float64) GeoPoint {
+	lat1 := start.Latitude.Degrees() * math.Pi / 180
+	lon1 := start.Longitude.Degrees() * math.Pi / 180
+	theta := bearing * math.Pi / 180
+	delta := km / EARTH_RADIUS_KM
+	lat2 := math.Asin(math.Sin(lat1)*math.Cos(delta) + math.Cos(lat1)*math.Sin(delta)*math.Cos(theta))
+	lon2 := lon1 + math.Atan2(math.Sin(theta)*math.Sin(delta)*math.Cos(lat1),
+		math.Cos(delta)-math.Sin(lat1)*math.Sin(lat2))
+	// Wrap the longitude back into [-180, 180].
+	lon2 = math.Remainder(lon2, 2*math.Pi)
+	return GeoPoint{Latitude: latitudeOf(lat2 * 180 / math.Pi), Longitude: longitudeOf(lon2 * 180 / math.Pi)}
+}
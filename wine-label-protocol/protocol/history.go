@@ -0,0 +1,64 @@
+package protocol
+
+import (
+	"fmt"
+)
+
+// HistoryEvent is one immutable entry in a label's provenance. Each event
+// names the hash of the one before it, so rewriting any event breaks every
+// link after it.
+type HistoryEvent struct {
+	WineLabelID string
+	Sequence    uint64
+	Verb        string
+	Signer      string
+	Location    string
+	// Position is only meaningful when HasPosition is set.
+	Position    GeoPoint
+	HasPosition bool
+	// ClaimedAt is the time the signer says the event happened. It is not
+	// checked against the validator's clock.
+	ClaimedAt    string
+	PreviousHash string
+}
+
+// HistoryHash returns the hash that the next event must carry as its
+// PreviousHash: the SHA-512 of the event exactly as it is stored.
+func HistoryHash(data []byte) string {
+	return Hexdigest(string(data))
+}
+
+// VerifyHistory checks that stored events, ordered by sequence, form an
+// unbroken chain for labelID that ends at head, and decodes them. head is
+// the HistoryHead of the label as stored, so that a history whose newest
+// events are missing is rejected; it is "" for a withdrawn label, whose
+// head is no longer kept, and then only the links are checked.
+func VerifyHistory(labelID string, stored [][]byte, head string) ([]HistoryEvent, error) {
+	events := make([]HistoryEvent, 0, len(stored))
+	previousHash := ""
+	for i, data := range stored {
+		var event HistoryEvent
+		if err := DecodeRecord(data, &event); err != nil {
+			return nil, fmt.Errorf("History event %d of %v: %v", i, labelID, err)
+		}
+		if event.WineLabelID != labelID {
+			return nil, fmt.Errorf("History event %d belongs to %v, not %v",
+				i, event.WineLabelID, labelID)
+		}
+		if event.Sequence != uint64(i) {
+			return nil, fmt.Errorf("History of %v has event %d where %d was expected",
+				labelID, event.Sequence, i)
+		}
+		if event.PreviousHash != previousHash {
+			return nil, fmt.Errorf("History of %v is broken at event %d: previous hash %v, want %v",
+				labelID, i, event.PreviousHash, previousHash)
+		}
+		previousHash = HistoryHash(data)
+		events = append(events, event)
+	}
+	if head != "" && previousHash != head {
+		return nil, fmt.Errorf("History of %v ends at event %d, which is not the label's latest",
+			labelID, len(stored)-1)
+	}
+	return events, nil
+}
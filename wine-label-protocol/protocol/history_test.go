@@ -0,0 +1,68 @@
+package protocol
+
+import (
+	"strings"
+	"testing"
+)
+
+func buildHistory(t *testing.T, labelID string, verbs ...string) [][]byte {
+	var stored [][]byte
+	previousHash := ""
+	for i, verb := range verbs {
+		data, err := EncodeRecord(HistoryEvent{
+			WineLabelID:  labelID,
+			Sequence:     uint64(i),
+			Verb:         verb,
+			Signer:       "02ab",
+			PreviousHash: previousHash,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		stored = append(stored, data)
+		previousHash = HistoryHash(data)
+	}
+	return stored
+}
+
+func TestVerifyHistory(t *testing.T) {
+	stored := buildHistory(t, "125", VERB_SET, VERB_APPLY, VERB_BOTTLE)
+	events, err := VerifyHistory("125", stored, HistoryHash(stored[2]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 3 || events[2].Verb != VERB_BOTTLE {
+		t.Errorf("VerifyHistory = %+v", events)
+	}
+}
+
+func TestVerifyHistoryDetectsTampering(t *testing.T) {
+	cases := []struct {
+		name   string
+		tamper func(stored [][]byte) [][]byte
+		error  string
+	}{
+		{"rewritten event", func(stored [][]byte) [][]byte {
+			forged, _ := EncodeRecord(HistoryEvent{WineLabelID: "125", Sequence: 1, Verb: VERB_VOID, Signer: "02ab"})
+			stored[1] = forged
+			return stored
+		}, "broken at event 1"},
+		{"dropped event", func(stored [][]byte) [][]byte {
+			return append(stored[:1], stored[2:]...)
+		}, "has event 2 where 1 was expected"},
+		{"newest event cut off", func(stored [][]byte) [][]byte {
+			return stored[:2]
+		}, "not the label's latest"},
+		{"other label", func(stored [][]byte) [][]byte {
+			return buildHistory(t, "126", VERB_SET)
+		}, "belongs to 126"},
+	}
+	for _, c := range cases {
+		stored := buildHistory(t, "125", VERB_SET, VERB_APPLY, VERB_BOTTLE)
+		head := HistoryHash(stored[2])
+		_, err := VerifyHistory("125", c.tamper(stored), head)
+		if err == nil || !strings.Contains(err.Error(), c.error) {
+			t.Errorf("%s: VerifyHistory error = %v, want %q", c.name, err, c.error)
+		}
+	}
+}
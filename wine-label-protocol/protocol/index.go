@@ -0,0 +1,56 @@
+package protocol
+
+// Secondary indexes list labels by owner, lot and facility without reading
+// every label in the namespace. An index entry lives at
+//
+//	prefix(6) | space(2) | hash(value)(30) | hash(label ID)(32)
+//
+// so all labels indexed under one value share an address prefix. The
+// spatial index in GEO_INDEX_SPACE is laid out differently, see geohash.go.
+const (
+	OWNER_INDEX_SPACE    string = "05"
+	LOT_INDEX_SPACE      string = "06"
+	FACILITY_INDEX_SPACE string = "07"
+	GEO_INDEX_SPACE      string = "08"
+
+	INDEX_VALUE_LENGTH int = 30
+)
+
+// INDEX_SPACES lists every secondary index.
+var INDEX_SPACES = []string{OWNER_INDEX_SPACE, LOT_INDEX_SPACE, FACILITY_INDEX_SPACE, GEO_INDEX_SPACE}
+
+// IndexEntry is stored at an index address and names the label it lists.
+type IndexEntry struct {
+	WineLabelID string
+}
+
+// IndexPrefix returns the address prefix of every label indexed under
+// value in an index space.
+func (ns Namespace) IndexPrefix(space string, value string) string {
+	return ns.SpacePrefix(space) + Hexdigest(value)[:INDEX_VALUE_LENGTH]
+}
+
+// IndexAddress returns the address of a label's entry under value in an
+// index space.
+func (ns Namespace) IndexAddress(space string, value string, labelID string) string {
+	keyLength := ADDRESS_LENGTH - NAMESPACE_PREFIX_LENGTH - SPACE_LENGTH - INDEX_VALUE_LENGTH
+	return ns.IndexPrefix(space, value) + Hexdigest(labelID)[:keyLength]
+}
+
+// IndexAddresses returns the addresses of every index entry a label
+// record should have. Empty values are not indexed; every label is
+// indexed by position.
+func (ns Namespace) IndexAddresses(record LabelRecord) []string {
+	values := map[string]string{
+		OWNER_INDEX_SPACE:    record.Owner,
+		LOT_INDEX_SPACE:      record.Lot,
+		FACILITY_INDEX_SPACE: record.Facility,
+	}
+	addresses := make([]string, 0, len(INDEX_SPACES))
+	for _, space := range INDEX_SPACES {
+		if values[space] != "" {
+			addresses = append(addresses, ns.IndexAddress(space, values[space], record.WineLabelID))
+		}
+	}
+	return append(addresses, ns.GeoIndexAddress(record.Position, record.WineLabelID))
+}
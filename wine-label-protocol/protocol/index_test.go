@@ -0,0 +1,42 @@
+package protocol
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestIndexAddress(t *testing.T) {
+	ns := NewNamespace(FAMILY_NAME)
+	address := ns.IndexAddress(OWNER_INDEX_SPACE, "02ab", "125")
+	want := "b2557605770c538aaadccd792de17d4b6c9413b7953ae09943b8bec668936bd8bda735"
+	if address != want {
+		t.Errorf("IndexAddress = %s, want %s", address, want)
+	}
+	if len(address) != ADDRESS_LENGTH {
+		t.Errorf("IndexAddress has length %d, want %d", len(address), ADDRESS_LENGTH)
+	}
+	if !strings.HasPrefix(address, ns.IndexPrefix(OWNER_INDEX_SPACE, "02ab")) {
+		t.Error("IndexAddress is not under IndexPrefix")
+	}
+	if strings.HasPrefix(address, ns.IndexPrefix(OWNER_INDEX_SPACE, "02ac")) {
+		t.Error("IndexAddress is under the prefix of another owner")
+	}
+}
+
+func TestIndexAddresses(t *testing.T) {
+	ns := NewNamespace(FAMILY_NAME)
+	record := LabelRecord{WineLabelID: "125", Owner: "02ab", Facility: "cellar"}
+	want := []string{
+		ns.IndexAddress(OWNER_INDEX_SPACE, "02ab", "125"),
+		ns.IndexAddress(FACILITY_INDEX_SPACE, "cellar", "125"),
+		ns.GeoIndexAddress(GeoPoint{}, "125"),
+	}
+	if addresses := ns.IndexAddresses(record); !reflect.DeepEqual(addresses, want) {
+		t.Errorf("IndexAddresses = %v, want %v", addresses, want)
+	}
+	record.Lot = "lot-2019"
+	if addresses := ns.IndexAddresses(record); len(addresses) != 4 {
+		t.Errorf("IndexAddresses = %v, want 4 addresses", addresses)
+	}
+}
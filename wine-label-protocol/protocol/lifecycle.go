@@ -0,0 +1,73 @@
+package protocol
+
+// LabelStatus is the point a label has reached in its life, from printing
+// to the bottle being opened.
+type LabelStatus string
+
+const (
+	STATUS_PRINTED   LabelStatus = "printed"
+	STATUS_APPLIED   LabelStatus = "applied"
+	STATUS_BOTTLED   LabelStatus = "bottled"
+	STATUS_SHIPPED   LabelStatus = "shipped"
+	STATUS_IN_RETAIL LabelStatus = "in-retail"
+	STATUS_SOLD      LabelStatus = "sold"
+	STATUS_OPENED    LabelStatus = "opened"
+	STATUS_VOIDED    LabelStatus = "voided"
+)
+
+// Lifecycle verbs, each moving a label to the status of the same name.
+const (
+	VERB_APPLY  string = "apply"
+	VERB_BOTTLE string = "bottle"
+	VERB_SHIP   string = "ship"
+	VERB_STOCK  string = "stock"
+	VERB_SELL   string = "sell"
+	VERB_OPEN   string = "open"
+	VERB_VOID   string = "void"
+)
+
+var verbStatus = map[string]LabelStatus{
+	VERB_APPLY:  STATUS_APPLIED,
+	VERB_BOTTLE: STATUS_BOTTLED,
+	VERB_SHIP:   STATUS_SHIPPED,
+	VERB_STOCK:  STATUS_IN_RETAIL,
+	VERB_SELL:   STATUS_SOLD,
+	VERB_OPEN:   STATUS_OPENED,
+	VERB_VOID:   STATUS_VOIDED,
+}
+
+// transitions lists the statuses each status may legally move to. A label
+// can be voided at any point before it is opened; opened and voided labels
+// are final.
+var transitions = map[LabelStatus][]LabelStatus{
+	STATUS_PRINTED:   {STATUS_APPLIED, STATUS_VOIDED},
+	STATUS_APPLIED:   {STATUS_BOTTLED, STATUS_VOIDED},
+	STATUS_BOTTLED:   {STATUS_SHIPPED, STATUS_VOIDED},
+	STATUS_SHIPPED:   {STATUS_IN_RETAIL, STATUS_VOIDED},
+	STATUS_IN_RETAIL: {STATUS_SOLD, STATUS_VOIDED},
+	STATUS_SOLD:      {STATUS_OPENED, STATUS_VOIDED},
+	STATUS_OPENED:    {},
+	STATUS_VOIDED:    {},
+}
+
+// StatusForVerb returns the status a lifecycle verb moves a label to, and
+// false if verb is not a lifecycle verb.
+func StatusForVerb(verb string) (LabelStatus, bool) {
+	status, ok := verbStatus[verb]
+	return status, ok
+}
+
+// CanTransition reports whether a label in status may move to next.
+func CanTransition(status, next LabelStatus) bool {
+	for _, allowed := range transitions[status] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// NextStatuses returns the statuses a label in status may move to.
+func NextStatuses(status LabelStatus) []LabelStatus {
+	return transitions[status]
+}
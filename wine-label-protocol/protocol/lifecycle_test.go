@@ -0,0 +1,61 @@
+package protocol
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCanTransition(t *testing.T) {
+	cases := []struct {
+		from, to LabelStatus
+		ok       bool
+	}{
+		{STATUS_PRINTED, STATUS_APPLIED, true},
+		{STATUS_APPLIED, STATUS_BOTTLED, true},
+		{STATUS_BOTTLED, STATUS_SHIPPED, true},
+		{STATUS_SHIPPED, STATUS_IN_RETAIL, true},
+		{STATUS_IN_RETAIL, STATUS_SOLD, true},
+		{STATUS_SOLD, STATUS_OPENED, true},
+		{STATUS_SOLD, STATUS_VOIDED, true},
+		{STATUS_PRINTED, STATUS_VOIDED, true},
+		{STATUS_PRINTED, STATUS_BOTTLED, false},
+		{STATUS_SHIPPED, STATUS_APPLIED, false},
+		{STATUS_OPENED, STATUS_VOIDED, false},
+		{STATUS_VOIDED, STATUS_PRINTED, false},
+		{"unknown", STATUS_APPLIED, false},
+	}
+	for _, c := range cases {
+		if got := CanTransition(c.from, c.to); got != c.ok {
+			t.Errorf("CanTransition(%v, %v) = %v, want %v", c.from, c.to, got, c.ok)
+		}
+	}
+}
+
+func TestStatusForVerb(t *testing.T) {
+	if status, ok := StatusForVerb(VERB_STOCK); !ok || status != STATUS_IN_RETAIL {
+		t.Errorf("StatusForVerb(%q) = %v, %v", VERB_STOCK, status, ok)
+	}
+	if _, ok := StatusForVerb(VERB_SET); ok {
+		t.Errorf("StatusForVerb(%q) reported a lifecycle verb", VERB_SET)
+	}
+}
+
+func TestLabelRecordRoundTrip(t *testing.T) {
+	record := LabelRecord{
+		WineLabelID: "125",
+		PrintedAt:   "loc",
+		Position:    GeoPoint{Latitude: -33868800, Longitude: 151209300},
+		Status:      STATUS_BOTTLED,
+	}
+	data, err := EncodeCBOR(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded LabelRecord
+	if err := DecodeCBOR(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, record) {
+		t.Errorf("DecodeCBOR = %+v, want %+v", decoded, record)
+	}
+}
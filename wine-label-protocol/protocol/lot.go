@@ -0,0 +1,123 @@
+package protocol
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// VERB_CREATE_LOT declares a lot of wine that labels can then be attached
+// to.
+const VERB_CREATE_LOT string = "create-lot"
+
+const (
+	// Vintages are plain years; a lot blended across years has none.
+	MIN_VINTAGE uint64 = 1800
+	MAX_VINTAGE uint64 = 9999
+)
+
+// Varietal is one grape variety of a lot and its share of the blend.
+type Varietal struct {
+	Grape   string
+	Percent uint64
+}
+
+// LotPayload carries the declaration of a lot for VERB_CREATE_LOT.
+type LotPayload struct {
+	LotID string
+	// Vintage is the harvest year, or 0 for a non-vintage wine.
+	Vintage     uint64
+	Varietals   []Varietal
+	Appellation string
+	BottleCount uint64
+}
+
+// Lot is the record stored at a lot address.
+type Lot struct {
+	LotID       string
+	Vintage     uint64
+	Varietals   []Varietal
+	Appellation string
+	// Producer is the organisation ID of the winery that declared the lot.
+	Producer    string
+	BottleCount uint64
+	// LabelCount is the number of labels attached to the lot, which may
+	// never exceed BottleCount.
+	LabelCount uint64
+	// Recall is set once the lot has been recalled. No more labels may be
+	// attached to a recalled lot.
+	Recall *Recall
+}
+
+// NewLot validates a lot declaration and builds the record to store for a
+// lot produced by the given winery.
+func NewLot(payload LotPayload, producer string) (Lot, error) {
+	if payload.LotID == "" {
+		return Lot{}, fmt.Errorf("Lot ID must not be empty")
+	}
+	if payload.Vintage != 0 && (payload.Vintage < MIN_VINTAGE || payload.Vintage > MAX_VINTAGE) {
+		return Lot{}, fmt.Errorf("Invalid vintage %d for lot %v", payload.Vintage, payload.LotID)
+	}
+	if payload.BottleCount == 0 {
+		return Lot{}, fmt.Errorf("Lot %v must declare a bottle count", payload.LotID)
+	}
+	if err := checkVarietals(payload.Varietals); err != nil {
+		return Lot{}, fmt.Errorf("Invalid composition of lot %v: %v", payload.LotID, err)
+	}
+	return Lot{
+		LotID:       payload.LotID,
+		Vintage:     payload.Vintage,
+		Varietals:   payload.Varietals,
+		Appellation: payload.Appellation,
+		Producer:    producer,
+		BottleCount: payload.BottleCount,
+	}, nil
+}
+
+// checkVarietals requires distinct, named grapes whose shares add up to
+// the whole blend.
+func checkVarietals(varietals []Varietal) error {
+	if len(varietals) == 0 {
+		return fmt.Errorf("no varietals")
+	}
+	seen := make(map[string]bool, len(varietals))
+	total := uint64(0)
+	for _, varietal := range varietals {
+		if varietal.Grape == "" {
+			return fmt.Errorf("varietal without a grape")
+		}
+		if seen[varietal.Grape] {
+			return fmt.Errorf("%v is listed twice", varietal.Grape)
+		}
+		seen[varietal.Grape] = true
+		if varietal.Percent == 0 || varietal.Percent > 100 {
+			return fmt.Errorf("%v has share %d%%", varietal.Grape, varietal.Percent)
+		}
+		total += varietal.Percent
+	}
+	if total != 100 {
+		return fmt.Errorf("shares add up to %d%%, not 100%%", total)
+	}
+	return nil
+}
+
+// ParseVarietal parses a varietal written as "grape:percent".
+func ParseVarietal(str string) (Varietal, error) {
+	i := strings.LastIndex(str, ":")
+	if i < 0 {
+		return Varietal{}, fmt.Errorf("Invalid varietal %q: expected grape:percent", str)
+	}
+	percent, err := strconv.ParseUint(str[i+1:], 10, 64)
+	if err != nil {
+		return Varietal{}, fmt.Errorf("Invalid varietal %q: %v", str, err)
+	}
+	return Varietal{Grape: str[:i], Percent: percent}, nil
+}
+
+// Remaining returns how many more labels may be attached to the lot.
+func (self Lot) Remaining() uint64 {
+	if self.LabelCount >= self.BottleCount {
+		return 0
+	}
+	return self.BottleCount - self.LabelCount
+}
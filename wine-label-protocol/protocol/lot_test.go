@@ -0,0 +1,58 @@
+package protocol
+
+import (
+	"testing"
+)
+
+func TestLotAddress(t *testing.T) {
+	ns := NewNamespace(FAMILY_NAME)
+	address := ns.LotAddress("lot-2019")
+	want := "b2557604a58a44f36f7b91b8e86a901f1860a93f32d810b95f2015c52cf8f2c38d656e"
+	if address != want {
+		t.Errorf("LotAddress = %s, want %s", address, want)
+	}
+	if len(address) != ADDRESS_LENGTH {
+		t.Errorf("LotAddress has length %d, want %d", len(address), ADDRESS_LENGTH)
+	}
+}
+
+func TestNewLot(t *testing.T) {
+	blend := []Varietal{{"Merlot", 60}, {"Cabernet Franc", 40}}
+	cases := []struct {
+		name    string
+		payload LotPayload
+		valid   bool
+	}{
+		{"vintage", LotPayload{"lot-2019", 2019, blend, "Saint-Emilion", 6000}, true},
+		{"non-vintage", LotPayload{"nv", 0, []Varietal{{"Chardonnay", 100}}, "", 100}, true},
+		{"no id", LotPayload{"", 2019, blend, "", 6000}, false},
+		{"bad vintage", LotPayload{"lot", 19, blend, "", 6000}, false},
+		{"no bottles", LotPayload{"lot", 2019, blend, "", 0}, false},
+		{"no varietals", LotPayload{"lot", 2019, nil, "", 6000}, false},
+		{"short blend", LotPayload{"lot", 2019, []Varietal{{"Merlot", 60}}, "", 6000}, false},
+		{"zero share", LotPayload{"lot", 2019, []Varietal{{"Merlot", 100}, {"Malbec", 0}}, "", 6000}, false},
+		{"repeated grape", LotPayload{"lot", 2019, []Varietal{{"Merlot", 50}, {"Merlot", 50}}, "", 6000}, false},
+		{"unnamed grape", LotPayload{"lot", 2019, []Varietal{{"", 100}}, "", 6000}, false},
+	}
+	for _, c := range cases {
+		lot, err := NewLot(c.payload, "chateau")
+		if (err == nil) != c.valid {
+			t.Errorf("%s: NewLot error = %v, want valid %v", c.name, err, c.valid)
+		}
+		if err == nil && (lot.Producer != "chateau" || lot.LabelCount != 0 || lot.Remaining() != c.payload.BottleCount) {
+			t.Errorf("%s: NewLot = %+v", c.name, lot)
+		}
+	}
+}
+
+func TestParseVarietal(t *testing.T) {
+	varietal, err := ParseVarietal("Cabernet Sauvignon:75")
+	if err != nil || varietal != (Varietal{"Cabernet Sauvignon", 75}) {
+		t.Errorf("ParseVarietal = %+v, %v", varietal, err)
+	}
+	for _, str := range []string{"Merlot", "Merlot:", "Merlot:60%", "Merlot:-5"} {
+		if _, err := ParseVarietal(str); err == nil {
+			t.Errorf("ParseVarietal accepted %q", str)
+		}
+	}
+}
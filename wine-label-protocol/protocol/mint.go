@@ -0,0 +1,131 @@
+package protocol
+
+import (
+	"fmt"
+	"math"
+)
+
+// VERB_MINT creates a roll of labels in a single transaction.
+const VERB_MINT string = "mint"
+
+const (
+	// MAX_MINT_COUNT bounds how many labels one mint transaction creates.
+	MAX_MINT_COUNT int = 1000
+	// MAX_MINT_DIGITS bounds the zero padding of numbered label IDs.
+	MAX_MINT_DIGITS uint64 = 20
+)
+
+// MintPayload carries the labels created by VERB_MINT: either the explicit
+// LabelIDs, or Count labels numbered from First, each ID being Prefix
+// followed by the number zero-padded to Digits digits. All of them share
+// the print metadata, and so the print time of the roll.
+type MintPayload struct {
+	LabelIDs []string
+	Prefix   string
+	First    uint64
+	Count    uint64
+	Digits   uint64
+
+	// Print is the roll's print event, checked as for VERB_SET. It
+	// supersedes Printer and Facility, which name the press of rolls
+	// minted without one.
+	Print     PrintEvent
+	Printer   string
+	Facility  string
+	PrintedAt string
+	Longitude string
+	Lattitude string
+	Lot       string
+	// Secrets optionally holds the scratch-off commitment of each label,
+	// by label ID.
+	Secrets map[string]LabelSecret
+}
+
+// Labels returns the IDs of the labels a mint payload creates, in order.
+func (self MintPayload) Labels() ([]string, error) {
+	ids, err := self.labels()
+	if err != nil || len(self.Secrets) == 0 {
+		return ids, err
+	}
+	minted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		minted[id] = true
+	}
+	for id := range self.Secrets {
+		if !minted[id] {
+			return nil, fmt.Errorf("Secret given for label %v, which is not minted", id)
+		}
+	}
+	return ids, nil
+}
+
+func (self MintPayload) labels() ([]string, error) {
+	if len(self.LabelIDs) > 0 && self.Count > 0 {
+		return nil, fmt.Errorf("Mint takes either a list of label IDs or a range, not both")
+	}
+	if len(self.LabelIDs) > 0 {
+		return self.listedLabels()
+	}
+	if self.Count == 0 {
+		return nil, fmt.Errorf("Mint needs a list of label IDs or a range count")
+	}
+	if self.Count > uint64(MAX_MINT_COUNT) {
+		return nil, fmt.Errorf("Cannot mint %d labels, the maximum is %d", self.Count, MAX_MINT_COUNT)
+	}
+	if self.First > math.MaxUint64-(self.Count-1) {
+		return nil, fmt.Errorf("Mint range from %d overflows", self.First)
+	}
+	if self.Digits > MAX_MINT_DIGITS {
+		return nil, fmt.Errorf("Cannot pad label numbers to %d digits, the maximum is %d",
+			self.Digits, MAX_MINT_DIGITS)
+	}
+	ids := make([]string, 0, self.Count)
+	for i := uint64(0); i < self.Count; i++ {
+		ids = append(ids, fmt.Sprintf("%s%0*d", self.Prefix, int(self.Digits), self.First+i))
+	}
+	return ids, nil
+}
+
+func (self MintPayload) listedLabels() ([]string, error) {
+	if len(self.LabelIDs) > MAX_MINT_COUNT {
+		return nil, fmt.Errorf("Cannot mint %d labels, the maximum is %d", len(self.LabelIDs), MAX_MINT_COUNT)
+	}
+	seen := make(map[string]bool, len(self.LabelIDs))
+	for _, id := range self.LabelIDs {
+		if id == "" {
+			return nil, fmt.Errorf("Cannot mint a label with an empty ID")
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("Label %v is listed twice", id)
+		}
+		seen[id] = true
+	}
+	return self.LabelIDs, nil
+}
+
+// LabelPayload returns the set payload equivalent to minting labelID.
+func (self MintPayload) LabelPayload(labelID string) Payload {
+	return Payload{
+		WineLabelID: labelID,
+		PrintedAt:   self.PrintedAt,
+		Longitude:   self.Longitude,
+		Lattitude:   self.Lattitude,
+		Lot:         self.Lot,
+		Secret:      self.Secrets[labelID],
+		Print:       self.Print,
+	}
+}
+
+// NewMintedRecord validates the shared metadata of a mint payload and
+// builds the record to store for labelID, owned by owner.
+func NewMintedRecord(mint MintPayload, labelID string, owner string) (LabelRecord, error) {
+	record, err := NewLabelRecord(mint.LabelPayload(labelID), owner)
+	if err != nil {
+		return record, err
+	}
+	if !mint.Print.IsSet() {
+		record.Printer = mint.Printer
+		record.Facility = mint.Facility
+	}
+	return record, nil
+}
@@ -0,0 +1,75 @@
+package protocol
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMintLabels(t *testing.T) {
+	cases := []struct {
+		name string
+		mint MintPayload
+		want []string
+	}{
+		{"range", MintPayload{Prefix: "W-", First: 9, Count: 3, Digits: 4}, []string{"W-0009", "W-0010", "W-0011"}},
+		{"unpadded range", MintPayload{First: 99, Count: 2}, []string{"99", "100"}},
+		{"list", MintPayload{LabelIDs: []string{"b", "a"}}, []string{"b", "a"}},
+	}
+	for _, c := range cases {
+		ids, err := c.mint.Labels()
+		if err != nil {
+			t.Errorf("%s: Labels: %v", c.name, err)
+		} else if !reflect.DeepEqual(ids, c.want) {
+			t.Errorf("%s: Labels = %v, want %v", c.name, ids, c.want)
+		}
+	}
+}
+
+func TestMintLabelsRejects(t *testing.T) {
+	cases := map[string]MintPayload{
+		"nothing":      {},
+		"both":         {LabelIDs: []string{"a"}, Count: 1},
+		"too many":     {Count: uint64(MAX_MINT_COUNT) + 1},
+		"overflow":     {First: ^uint64(0), Count: 2},
+		"wide padding": {Count: 1, Digits: MAX_MINT_DIGITS + 1},
+		"duplicate":    {LabelIDs: []string{"a", "b", "a"}},
+		"empty id":     {LabelIDs: []string{"a", ""}},
+	}
+	for name, mint := range cases {
+		if ids, err := mint.Labels(); err == nil {
+			t.Errorf("%s: Labels = %v, want an error", name, ids)
+		}
+	}
+}
+
+func TestNewMintedRecord(t *testing.T) {
+	mint := MintPayload{Printer: "press-1", Facility: "cellar", Lot: "lot-2019",
+		PrintedAt: "loc", Lattitude: "44.83", Longitude: "-0.57"}
+	record, err := NewMintedRecord(mint, "W-0001", "02ab")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := LabelRecord{
+		WineLabelID: "W-0001",
+		PrintedAt:   "loc",
+		Position:    GeoPoint{Latitude: 44830000, Longitude: -570000},
+		Status:      STATUS_PRINTED,
+		Owner:       "02ab",
+		Printer:     "press-1",
+		Facility:    "cellar",
+		Lot:         "lot-2019",
+	}
+	if !reflect.DeepEqual(record, want) {
+		t.Errorf("NewMintedRecord = %+v, want %+v", record, want)
+	}
+	mint.Print = PrintEvent{"2020-01-02T03:04:05Z", "press-2", "warehouse", "alice"}
+	record, _ = NewMintedRecord(mint, "W-0001", "02ab")
+	if record.Printer != "press-2" || record.Facility != "warehouse" ||
+		record.PrintTime != mint.Print.Time || record.Operator != "alice" {
+		t.Errorf("NewMintedRecord with a print event = %+v", record)
+	}
+	mint.Lattitude = "91"
+	if _, err := NewMintedRecord(mint, "W-0001", "02ab"); err == nil {
+		t.Error("NewMintedRecord accepted latitude 91")
+	}
+}
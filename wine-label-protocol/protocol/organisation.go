@@ -0,0 +1,109 @@
+package protocol
+
+import (
+	"encoding/hex"
+	"fmt"
+)
+
+// OrgType is the role an organisation plays in the supply chain. It is an
+// alias of string.
+type OrgType = string
+
+const (
+	ORG_WINERY      OrgType = "winery"
+	ORG_PRINTER     OrgType = "printer"
+	ORG_DISTRIBUTOR OrgType = "distributor"
+	ORG_RETAILER    OrgType = "retailer"
+)
+
+// Registry verbs.
+const (
+	VERB_REGISTER_ORG string = "register-org"
+	VERB_UPDATE_ORG   string = "update-org"
+)
+
+// PUBLIC_KEY_LENGTH is the length of a hex encoded compressed secp256k1
+// public key.
+const PUBLIC_KEY_LENGTH int = 66
+
+// OrgPayload carries the organisation fields of VERB_REGISTER_ORG and
+// VERB_UPDATE_ORG.
+type OrgPayload struct {
+	OrgID string
+	Name  string
+	Type  OrgType
+	Keys  []string
+}
+
+// Organisation is the registry record stored at an organisation address.
+type Organisation struct {
+	OrgID string
+	Name  string
+	Type  OrgType
+	// Keys are the public keys authorised to act for the organisation.
+	Keys []string
+}
+
+// KeyRecord maps a public key to the organisation it is registered to. A
+// key belongs to at most one organisation.
+type KeyRecord struct {
+	PublicKey string
+	OrgID     string
+}
+
+// CanCreateLabels reports whether members of an organisation of this type
+// may create labels: wineries and the printers licensed through the
+// registry.
+func CanCreateLabels(orgType OrgType) bool {
+	return orgType == ORG_WINERY || orgType == ORG_PRINTER
+}
+
+// NewOrganisation validates an organisation payload and builds the record
+// to store for it.
+func NewOrganisation(payload OrgPayload) (Organisation, error) {
+	if payload.OrgID == "" {
+		return Organisation{}, fmt.Errorf("Organisation ID is required")
+	}
+	switch payload.Type {
+	case ORG_WINERY, ORG_PRINTER, ORG_DISTRIBUTOR, ORG_RETAILER:
+	default:
+		return Organisation{}, fmt.Errorf("Invalid organisation type: %q", payload.Type)
+	}
+	if len(payload.Keys) == 0 {
+		return Organisation{}, fmt.Errorf("Organisation %v needs at least one key", payload.OrgID)
+	}
+	seen := make(map[string]bool)
+	for _, key := range payload.Keys {
+		if !ValidPublicKey(key) {
+			return Organisation{}, fmt.Errorf("Invalid public key: %q", key)
+		}
+		if seen[key] {
+			return Organisation{}, fmt.Errorf("Duplicate public key: %v", key)
+		}
+		seen[key] = true
+	}
+	return Organisation{
+		OrgID: payload.OrgID,
+		Name:  payload.Name,
+		Type:  payload.Type,
+		Keys:  payload.Keys,
+	}, nil
+}
+
+// HasKey reports whether key is authorised to act for the organisation.
+func (org Organisation) HasKey(key string) bool {
+	for _, orgKey := range org.Keys {
+		if orgKey == key {
+			return true
+		}
+	}
+	return false
+}
+
+func ValidPublicKey(key string) bool {
+	if len(key) != PUBLIC_KEY_LENGTH {
+		return false
+	}
+	_, err := hex.DecodeString(key)
+	return err == nil
+}
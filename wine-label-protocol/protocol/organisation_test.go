@@ -0,0 +1,45 @@
+package protocol
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewOrganisation(t *testing.T) {
+	key := strings.Repeat("02", 33)
+	other := strings.Repeat("03", 33)
+	cases := []struct {
+		name    string
+		payload OrgPayload
+		ok      bool
+	}{
+		{"winery", OrgPayload{"chateau", "Chateau", ORG_WINERY, []string{key, other}}, true},
+		{"retailer", OrgPayload{"shop", "", ORG_RETAILER, []string{key}}, true},
+		{"no id", OrgPayload{"", "Chateau", ORG_WINERY, []string{key}}, false},
+		{"bad type", OrgPayload{"chateau", "Chateau", "vineyard", []string{key}}, false},
+		{"no keys", OrgPayload{"chateau", "Chateau", ORG_WINERY, nil}, false},
+		{"short key", OrgPayload{"chateau", "Chateau", ORG_WINERY, []string{"02ab"}}, false},
+		{"duplicate key", OrgPayload{"chateau", "Chateau", ORG_WINERY, []string{key, key}}, false},
+	}
+	for _, c := range cases {
+		_, err := NewOrganisation(c.payload)
+		if c.ok && err != nil {
+			t.Errorf("%s: %v", c.name, err)
+		} else if !c.ok && err == nil {
+			t.Errorf("%s: NewOrganisation accepted %+v", c.name, c.payload)
+		}
+	}
+}
+
+func TestCanCreateLabels(t *testing.T) {
+	for orgType, want := range map[OrgType]bool{
+		ORG_WINERY:      true,
+		ORG_PRINTER:     true,
+		ORG_DISTRIBUTOR: false,
+		ORG_RETAILER:    false,
+	} {
+		if got := CanCreateLabels(orgType); got != want {
+			t.Errorf("CanCreateLabels(%v) = %v, want %v", orgType, got, want)
+		}
+	}
+}
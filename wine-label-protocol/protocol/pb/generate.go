@@ -0,0 +1,6 @@
+// Package pb is the generated protobuf encoding of the wine-label payloads
+// and state records. Use the conversions in package protocol rather than
+// building these messages by hand.
+package pb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative wine_label.proto
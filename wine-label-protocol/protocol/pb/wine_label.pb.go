@@ -0,0 +1,2130 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.25.0
+// 	protoc        (unknown)
+// source: wine_label.proto
+
+package pb
+
+import (
+	proto "github.com/golang/protobuf/proto"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+type Envelope struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version uint64 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Body    []byte `protobuf:"bytes,2,opt,name=body,proto3" json:"body,omitempty"`
+}
+
+func (x *Envelope) Reset() {
+	*x = Envelope{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_wine_label_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Envelope) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Envelope) ProtoMessage() {}
+
+func (x *Envelope) ProtoReflect() protoreflect.Message {
+	mi := &file_wine_label_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Envelope.ProtoReflect.Descriptor instead.
+func (*Envelope) Descriptor() ([]byte, []int) {
+	return file_wine_label_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Envelope) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Envelope) GetBody() []byte {
+	if x != nil {
+		return x.Body
+	}
+	return nil
+}
+
+type Payload struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	WineLabelId string       `protobuf:"bytes,1,opt,name=wine_label_id,json=wineLabelId,proto3" json:"wine_label_id,omitempty"`
+	PrintedAt   string       `protobuf:"bytes,2,opt,name=printed_at,json=printedAt,proto3" json:"printed_at,omitempty"`
+	Longitude   string       `protobuf:"bytes,3,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	Latitude    string       `protobuf:"bytes,4,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Lot         string       `protobuf:"bytes,5,opt,name=lot,proto3" json:"lot,omitempty"`
+	Secret      *LabelSecret `protobuf:"bytes,6,opt,name=secret,proto3" json:"secret,omitempty"`
+	Print       *PrintEvent  `protobuf:"bytes,7,opt,name=print,proto3" json:"print,omitempty"`
+}
+
+func (x *Payload) Reset() {
+	*x = Payload{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_wine_label_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Payload) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Payload) ProtoMessage() {}
+
+func (x *Payload) ProtoReflect() protoreflect.Message {
+	mi := &file_wine_label_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Payload.ProtoReflect.Descriptor instead.
+func (*Payload) Descriptor() ([]byte, []int) {
+	return file_wine_label_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Payload) GetWineLabelId() string {
+	if x != nil {
+		return x.WineLabelId
+	}
+	return ""
+}
+
+func (x *Payload) GetPrintedAt() string {
+	if x != nil {
+		return x.PrintedAt
+	}
+	return ""
+}
+
+func (x *Payload) GetLongitude() string {
+	if x != nil {
+		return x.Longitude
+	}
+	return ""
+}
+
+func (x *Payload) GetLatitude() string {
+	if x != nil {
+		return x.Latitude
+	}
+	return ""
+}
+
+func (x *Payload) GetLot() string {
+	if x != nil {
+		return x.Lot
+	}
+	return ""
+}
+
+func (x *Payload) GetSecret() *LabelSecret {
+	if x != nil {
+		return x.Secret
+	}
+	return nil
+}
+
+func (x *Payload) GetPrint() *PrintEvent {
+	if x != nil {
+		return x.Print
+	}
+	return nil
+}
+
+type PrintEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Time     string `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	Printer  string `protobuf:"bytes,2,opt,name=printer,proto3" json:"printer,omitempty"`
+	Facility string `protobuf:"bytes,3,opt,name=facility,proto3" json:"facility,omitempty"`
+	Operator string `protobuf:"bytes,4,opt,name=operator,proto3" json:"operator,omitempty"`
+}
+
+func (x *PrintEvent) Reset() {
+	*x = PrintEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_wine_label_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PrintEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PrintEvent) ProtoMessage() {}
+
+func (x *PrintEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_wine_label_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PrintEvent.ProtoReflect.Descriptor instead.
+func (*PrintEvent) Descriptor() ([]byte, []int) {
+	return file_wine_label_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PrintEvent) GetTime() string {
+	if x != nil {
+		return x.Time
+	}
+	return ""
+}
+
+func (x *PrintEvent) GetPrinter() string {
+	if x != nil {
+		return x.Printer
+	}
+	return ""
+}
+
+func (x *PrintEvent) GetFacility() string {
+	if x != nil {
+		return x.Facility
+	}
+	return ""
+}
+
+func (x *PrintEvent) GetOperator() string {
+	if x != nil {
+		return x.Operator
+	}
+	return ""
+}
+
+type LabelSecret struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Salt string `protobuf:"bytes,1,opt,name=salt,proto3" json:"salt,omitempty"`
+	Hash string `protobuf:"bytes,2,opt,name=hash,proto3" json:"hash,omitempty"`
+}
+
+func (x *LabelSecret) Reset() {
+	*x = LabelSecret{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_wine_label_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LabelSecret) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LabelSecret) ProtoMessage() {}
+
+func (x *LabelSecret) ProtoReflect() protoreflect.Message {
+	mi := &file_wine_label_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LabelSecret.ProtoReflect.Descriptor instead.
+func (*LabelSecret) Descriptor() ([]byte, []int) {
+	return file_wine_label_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *LabelSecret) GetSalt() string {
+	if x != nil {
+		return x.Salt
+	}
+	return ""
+}
+
+func (x *LabelSecret) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+type OrgPayload struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OrgId string   `protobuf:"bytes,1,opt,name=org_id,json=orgId,proto3" json:"org_id,omitempty"`
+	Name  string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Type  string   `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Keys  []string `protobuf:"bytes,4,rep,name=keys,proto3" json:"keys,omitempty"`
+}
+
+func (x *OrgPayload) Reset() {
+	*x = OrgPayload{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_wine_label_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OrgPayload) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrgPayload) ProtoMessage() {}
+
+func (x *OrgPayload) ProtoReflect() protoreflect.Message {
+	mi := &file_wine_label_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrgPayload.ProtoReflect.Descriptor instead.
+func (*OrgPayload) Descriptor() ([]byte, []int) {
+	return file_wine_label_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *OrgPayload) GetOrgId() string {
+	if x != nil {
+		return x.OrgId
+	}
+	return ""
+}
+
+func (x *OrgPayload) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *OrgPayload) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *OrgPayload) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+type WineLabelPayload struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Payload        *Payload     `protobuf:"bytes,1,opt,name=payload,proto3" json:"payload,omitempty"`
+	Verb           string       `protobuf:"bytes,2,opt,name=verb,proto3" json:"verb,omitempty"`
+	Recipient      string       `protobuf:"bytes,3,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	ClaimedAt      string       `protobuf:"bytes,4,opt,name=claimed_at,json=claimedAt,proto3" json:"claimed_at,omitempty"`
+	Organisation   *OrgPayload  `protobuf:"bytes,5,opt,name=organisation,proto3" json:"organisation,omitempty"`
+	Mint           *MintPayload `protobuf:"bytes,6,opt,name=mint,proto3" json:"mint,omitempty"`
+	LotDetails     *LotPayload  `protobuf:"bytes,7,opt,name=lot_details,json=lotDetails,proto3" json:"lot_details,omitempty"`
+	Reason         string       `protobuf:"bytes,8,opt,name=reason,proto3" json:"reason,omitempty"`
+	RevealedSecret string       `protobuf:"bytes,9,opt,name=revealed_secret,json=revealedSecret,proto3" json:"revealed_secret,omitempty"`
+}
+
+func (x *WineLabelPayload) Reset() {
+	*x = WineLabelPayload{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_wine_label_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WineLabelPayload) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WineLabelPayload) ProtoMessage() {}
+
+func (x *WineLabelPayload) ProtoReflect() protoreflect.Message {
+	mi := &file_wine_label_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WineLabelPayload.ProtoReflect.Descriptor instead.
+func (*WineLabelPayload) Descriptor() ([]byte, []int) {
+	return file_wine_label_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *WineLabelPayload) GetPayload() *Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *WineLabelPayload) GetVerb() string {
+	if x != nil {
+		return x.Verb
+	}
+	return ""
+}
+
+func (x *WineLabelPayload) GetRecipient() string {
+	if x != nil {
+		return x.Recipient
+	}
+	return ""
+}
+
+func (x *WineLabelPayload) GetClaimedAt() string {
+	if x != nil {
+		return x.ClaimedAt
+	}
+	return ""
+}
+
+func (x *WineLabelPayload) GetOrganisation() *OrgPayload {
+	if x != nil {
+		return x.Organisation
+	}
+	return nil
+}
+
+func (x *WineLabelPayload) GetMint() *MintPayload {
+	if x != nil {
+		return x.Mint
+	}
+	return nil
+}
+
+func (x *WineLabelPayload) GetLotDetails() *LotPayload {
+	if x != nil {
+		return x.LotDetails
+	}
+	return nil
+}
+
+func (x *WineLabelPayload) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *WineLabelPayload) GetRevealedSecret() string {
+	if x != nil {
+		return x.RevealedSecret
+	}
+	return ""
+}
+
+type MintPayload struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LabelIds  []string                `protobuf:"bytes,1,rep,name=label_ids,json=labelIds,proto3" json:"label_ids,omitempty"`
+	Prefix    string                  `protobuf:"bytes,2,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	First     uint64                  `protobuf:"varint,3,opt,name=first,proto3" json:"first,omitempty"`
+	Count     uint64                  `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`
+	Digits    uint64                  `protobuf:"varint,5,opt,name=digits,proto3" json:"digits,omitempty"`
+	Printer   string                  `protobuf:"bytes,6,opt,name=printer,proto3" json:"printer,omitempty"`
+	Facility  string                  `protobuf:"bytes,7,opt,name=facility,proto3" json:"facility,omitempty"`
+	PrintedAt string                  `protobuf:"bytes,8,opt,name=printed_at,json=printedAt,proto3" json:"printed_at,omitempty"`
+	Longitude string                  `protobuf:"bytes,9,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	Latitude  string                  `protobuf:"bytes,10,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Lot       string                  `protobuf:"bytes,11,opt,name=lot,proto3" json:"lot,omitempty"`
+	Secrets   map[string]*LabelSecret `protobuf:"bytes,12,rep,name=secrets,proto3" json:"secrets,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Print     *PrintEvent             `protobuf:"bytes,13,opt,name=print,proto3" json:"print,omitempty"`
+}
+
+func (x *MintPayload) Reset() {
+	*x = MintPayload{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_wine_label_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MintPayload) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MintPayload) ProtoMessage() {}
+
+func (x *MintPayload) ProtoReflect() protoreflect.Message {
+	mi := &file_wine_label_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MintPayload.ProtoReflect.Descriptor instead.
+func (*MintPayload) Descriptor() ([]byte, []int) {
+	return file_wine_label_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *MintPayload) GetLabelIds() []string {
+	if x != nil {
+		return x.LabelIds
+	}
+	return nil
+}
+
+func (x *MintPayload) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *MintPayload) GetFirst() uint64 {
+	if x != nil {
+		return x.First
+	}
+	return 0
+}
+
+func (x *MintPayload) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *MintPayload) GetDigits() uint64 {
+	if x != nil {
+		return x.Digits
+	}
+	return 0
+}
+
+func (x *MintPayload) GetPrinter() string {
+	if x != nil {
+		return x.Printer
+	}
+	return ""
+}
+
+func (x *MintPayload) GetFacility() string {
+	if x != nil {
+		return x.Facility
+	}
+	return ""
+}
+
+func (x *MintPayload) GetPrintedAt() string {
+	if x != nil {
+		return x.PrintedAt
+	}
+	return ""
+}
+
+func (x *MintPayload) GetLongitude() string {
+	if x != nil {
+		return x.Longitude
+	}
+	return ""
+}
+
+func (x *MintPayload) GetLatitude() string {
+	if x != nil {
+		return x.Latitude
+	}
+	return ""
+}
+
+func (x *MintPayload) GetLot() string {
+	if x != nil {
+		return x.Lot
+	}
+	return ""
+}
+
+func (x *MintPayload) GetSecrets() map[string]*LabelSecret {
+	if x != nil {
+		return x.Secrets
+	}
+	return nil
+}
+
+func (x *MintPayload) GetPrint() *PrintEvent {
+	if x != nil {
+		return x.Print
+	}
+	return nil
+}
+
+type Varietal struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Grape   string `protobuf:"bytes,1,opt,name=grape,proto3" json:"grape,omitempty"`
+	Percent uint64 `protobuf:"varint,2,opt,name=percent,proto3" json:"percent,omitempty"`
+}
+
+func (x *Varietal) Reset() {
+	*x = Varietal{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_wine_label_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Varietal) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Varietal) ProtoMessage() {}
+
+func (x *Varietal) ProtoReflect() protoreflect.Message {
+	mi := &file_wine_label_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Varietal.ProtoReflect.Descriptor instead.
+func (*Varietal) Descriptor() ([]byte, []int) {
+	return file_wine_label_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Varietal) GetGrape() string {
+	if x != nil {
+		return x.Grape
+	}
+	return ""
+}
+
+func (x *Varietal) GetPercent() uint64 {
+	if x != nil {
+		return x.Percent
+	}
+	return 0
+}
+
+type LotPayload struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LotId       string      `protobuf:"bytes,1,opt,name=lot_id,json=lotId,proto3" json:"lot_id,omitempty"`
+	Vintage     uint64      `protobuf:"varint,2,opt,name=vintage,proto3" json:"vintage,omitempty"`
+	Varietals   []*Varietal `protobuf:"bytes,3,rep,name=varietals,proto3" json:"varietals,omitempty"`
+	Appellation string      `protobuf:"bytes,4,opt,name=appellation,proto3" json:"appellation,omitempty"`
+	BottleCount uint64      `protobuf:"varint,5,opt,name=bottle_count,json=bottleCount,proto3" json:"bottle_count,omitempty"`
+}
+
+func (x *LotPayload) Reset() {
+	*x = LotPayload{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_wine_label_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LotPayload) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LotPayload) ProtoMessage() {}
+
+func (x *LotPayload) ProtoReflect() protoreflect.Message {
+	mi := &file_wine_label_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LotPayload.ProtoReflect.Descriptor instead.
+func (*LotPayload) Descriptor() ([]byte, []int) {
+	return file_wine_label_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *LotPayload) GetLotId() string {
+	if x != nil {
+		return x.LotId
+	}
+	return ""
+}
+
+func (x *LotPayload) GetVintage() uint64 {
+	if x != nil {
+		return x.Vintage
+	}
+	return 0
+}
+
+func (x *LotPayload) GetVarietals() []*Varietal {
+	if x != nil {
+		return x.Varietals
+	}
+	return nil
+}
+
+func (x *LotPayload) GetAppellation() string {
+	if x != nil {
+		return x.Appellation
+	}
+	return ""
+}
+
+func (x *LotPayload) GetBottleCount() uint64 {
+	if x != nil {
+		return x.BottleCount
+	}
+	return 0
+}
+
+type GeoPoint struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Latitude  int64 `protobuf:"varint,1,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude int64 `protobuf:"varint,2,opt,name=longitude,proto3" json:"longitude,omitempty"`
+}
+
+func (x *GeoPoint) Reset() {
+	*x = GeoPoint{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_wine_label_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GeoPoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeoPoint) ProtoMessage() {}
+
+func (x *GeoPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_wine_label_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeoPoint.ProtoReflect.Descriptor instead.
+func (*GeoPoint) Descriptor() ([]byte, []int) {
+	return file_wine_label_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GeoPoint) GetLatitude() int64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *GeoPoint) GetLongitude() int64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+type LabelRecord struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	WineLabelId      string       `protobuf:"bytes,1,opt,name=wine_label_id,json=wineLabelId,proto3" json:"wine_label_id,omitempty"`
+	PrintedAt        string       `protobuf:"bytes,2,opt,name=printed_at,json=printedAt,proto3" json:"printed_at,omitempty"`
+	Position         *GeoPoint    `protobuf:"bytes,3,opt,name=position,proto3" json:"position,omitempty"`
+	Status           string       `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	Owner            string       `protobuf:"bytes,5,opt,name=owner,proto3" json:"owner,omitempty"`
+	PendingOwner     string       `protobuf:"bytes,6,opt,name=pending_owner,json=pendingOwner,proto3" json:"pending_owner,omitempty"`
+	HistoryLength    uint64       `protobuf:"varint,7,opt,name=history_length,json=historyLength,proto3" json:"history_length,omitempty"`
+	HistoryHead      string       `protobuf:"bytes,8,opt,name=history_head,json=historyHead,proto3" json:"history_head,omitempty"`
+	Printer          string       `protobuf:"bytes,9,opt,name=printer,proto3" json:"printer,omitempty"`
+	Facility         string       `protobuf:"bytes,10,opt,name=facility,proto3" json:"facility,omitempty"`
+	Lot              string       `protobuf:"bytes,11,opt,name=lot,proto3" json:"lot,omitempty"`
+	ScanCount        uint64       `protobuf:"varint,12,opt,name=scan_count,json=scanCount,proto3" json:"scan_count,omitempty"`
+	LastScan         *Scan        `protobuf:"bytes,13,opt,name=last_scan,json=lastScan,proto3" json:"last_scan,omitempty"`
+	ScanRegions      []string     `protobuf:"bytes,14,rep,name=scan_regions,json=scanRegions,proto3" json:"scan_regions,omitempty"`
+	Suspicious       bool         `protobuf:"varint,15,opt,name=suspicious,proto3" json:"suspicious,omitempty"`
+	SuspiciousReason string       `protobuf:"bytes,16,opt,name=suspicious_reason,json=suspiciousReason,proto3" json:"suspicious_reason,omitempty"`
+	Secret           *LabelSecret `protobuf:"bytes,17,opt,name=secret,proto3" json:"secret,omitempty"`
+	Claim            *Claim       `protobuf:"bytes,18,opt,name=claim,proto3" json:"claim,omitempty"`
+	PrintTime        string       `protobuf:"bytes,19,opt,name=print_time,json=printTime,proto3" json:"print_time,omitempty"`
+	Operator         string       `protobuf:"bytes,20,opt,name=operator,proto3" json:"operator,omitempty"`
+}
+
+func (x *LabelRecord) Reset() {
+	*x = LabelRecord{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_wine_label_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LabelRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LabelRecord) ProtoMessage() {}
+
+func (x *LabelRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_wine_label_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LabelRecord.ProtoReflect.Descriptor instead.
+func (*LabelRecord) Descriptor() ([]byte, []int) {
+	return file_wine_label_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *LabelRecord) GetWineLabelId() string {
+	if x != nil {
+		return x.WineLabelId
+	}
+	return ""
+}
+
+func (x *LabelRecord) GetPrintedAt() string {
+	if x != nil {
+		return x.PrintedAt
+	}
+	return ""
+}
+
+func (x *LabelRecord) GetPosition() *GeoPoint {
+	if x != nil {
+		return x.Position
+	}
+	return nil
+}
+
+func (x *LabelRecord) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *LabelRecord) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *LabelRecord) GetPendingOwner() string {
+	if x != nil {
+		return x.PendingOwner
+	}
+	return ""
+}
+
+func (x *LabelRecord) GetHistoryLength() uint64 {
+	if x != nil {
+		return x.HistoryLength
+	}
+	return 0
+}
+
+func (x *LabelRecord) GetHistoryHead() string {
+	if x != nil {
+		return x.HistoryHead
+	}
+	return ""
+}
+
+func (x *LabelRecord) GetPrinter() string {
+	if x != nil {
+		return x.Printer
+	}
+	return ""
+}
+
+func (x *LabelRecord) GetFacility() string {
+	if x != nil {
+		return x.Facility
+	}
+	return ""
+}
+
+func (x *LabelRecord) GetLot() string {
+	if x != nil {
+		return x.Lot
+	}
+	return ""
+}
+
+func (x *LabelRecord) GetScanCount() uint64 {
+	if x != nil {
+		return x.ScanCount
+	}
+	return 0
+}
+
+func (x *LabelRecord) GetLastScan() *Scan {
+	if x != nil {
+		return x.LastScan
+	}
+	return nil
+}
+
+func (x *LabelRecord) GetScanRegions() []string {
+	if x != nil {
+		return x.ScanRegions
+	}
+	return nil
+}
+
+func (x *LabelRecord) GetSuspicious() bool {
+	if x != nil {
+		return x.Suspicious
+	}
+	return false
+}
+
+func (x *LabelRecord) GetSuspiciousReason() string {
+	if x != nil {
+		return x.SuspiciousReason
+	}
+	return ""
+}
+
+func (x *LabelRecord) GetSecret() *LabelSecret {
+	if x != nil {
+		return x.Secret
+	}
+	return nil
+}
+
+func (x *LabelRecord) GetClaim() *Claim {
+	if x != nil {
+		return x.Claim
+	}
+	return nil
+}
+
+func (x *LabelRecord) GetPrintTime() string {
+	if x != nil {
+		return x.PrintTime
+	}
+	return ""
+}
+
+func (x *LabelRecord) GetOperator() string {
+	if x != nil {
+		return x.Operator
+	}
+	return ""
+}
+
+type Claim struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Claimant  string `protobuf:"bytes,1,opt,name=claimant,proto3" json:"claimant,omitempty"`
+	ClaimedAt string `protobuf:"bytes,2,opt,name=claimed_at,json=claimedAt,proto3" json:"claimed_at,omitempty"`
+}
+
+func (x *Claim) Reset() {
+	*x = Claim{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_wine_label_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Claim) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Claim) ProtoMessage() {}
+
+func (x *Claim) ProtoReflect() protoreflect.Message {
+	mi := &file_wine_label_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Claim.ProtoReflect.Descriptor instead.
+func (*Claim) Descriptor() ([]byte, []int) {
+	return file_wine_label_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Claim) GetClaimant() string {
+	if x != nil {
+		return x.Claimant
+	}
+	return ""
+}
+
+func (x *Claim) GetClaimedAt() string {
+	if x != nil {
+		return x.ClaimedAt
+	}
+	return ""
+}
+
+type Scan struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Scanner   string    `protobuf:"bytes,1,opt,name=scanner,proto3" json:"scanner,omitempty"`
+	Position  *GeoPoint `protobuf:"bytes,2,opt,name=position,proto3" json:"position,omitempty"`
+	ScannedAt string    `protobuf:"bytes,3,opt,name=scanned_at,json=scannedAt,proto3" json:"scanned_at,omitempty"`
+}
+
+func (x *Scan) Reset() {
+	*x = Scan{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_wine_label_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Scan) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Scan) ProtoMessage() {}
+
+func (x *Scan) ProtoReflect() protoreflect.Message {
+	mi := &file_wine_label_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Scan.ProtoReflect.Descriptor instead.
+func (*Scan) Descriptor() ([]byte, []int) {
+	return file_wine_label_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Scan) GetScanner() string {
+	if x != nil {
+		return x.Scanner
+	}
+	return ""
+}
+
+func (x *Scan) GetPosition() *GeoPoint {
+	if x != nil {
+		return x.Position
+	}
+	return nil
+}
+
+func (x *Scan) GetScannedAt() string {
+	if x != nil {
+		return x.ScannedAt
+	}
+	return ""
+}
+
+type HistoryEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	WineLabelId  string    `protobuf:"bytes,1,opt,name=wine_label_id,json=wineLabelId,proto3" json:"wine_label_id,omitempty"`
+	Sequence     uint64    `protobuf:"varint,2,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Verb         string    `protobuf:"bytes,3,opt,name=verb,proto3" json:"verb,omitempty"`
+	Signer       string    `protobuf:"bytes,4,opt,name=signer,proto3" json:"signer,omitempty"`
+	Location     string    `protobuf:"bytes,5,opt,name=location,proto3" json:"location,omitempty"`
+	Position     *GeoPoint `protobuf:"bytes,6,opt,name=position,proto3" json:"position,omitempty"`
+	HasPosition  bool      `protobuf:"varint,7,opt,name=has_position,json=hasPosition,proto3" json:"has_position,omitempty"`
+	ClaimedAt    string    `protobuf:"bytes,8,opt,name=claimed_at,json=claimedAt,proto3" json:"claimed_at,omitempty"`
+	PreviousHash string    `protobuf:"bytes,9,opt,name=previous_hash,json=previousHash,proto3" json:"previous_hash,omitempty"`
+}
+
+func (x *HistoryEvent) Reset() {
+	*x = HistoryEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_wine_label_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HistoryEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryEvent) ProtoMessage() {}
+
+func (x *HistoryEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_wine_label_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryEvent.ProtoReflect.Descriptor instead.
+func (*HistoryEvent) Descriptor() ([]byte, []int) {
+	return file_wine_label_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *HistoryEvent) GetWineLabelId() string {
+	if x != nil {
+		return x.WineLabelId
+	}
+	return ""
+}
+
+func (x *HistoryEvent) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *HistoryEvent) GetVerb() string {
+	if x != nil {
+		return x.Verb
+	}
+	return ""
+}
+
+func (x *HistoryEvent) GetSigner() string {
+	if x != nil {
+		return x.Signer
+	}
+	return ""
+}
+
+func (x *HistoryEvent) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *HistoryEvent) GetPosition() *GeoPoint {
+	if x != nil {
+		return x.Position
+	}
+	return nil
+}
+
+func (x *HistoryEvent) GetHasPosition() bool {
+	if x != nil {
+		return x.HasPosition
+	}
+	return false
+}
+
+func (x *HistoryEvent) GetClaimedAt() string {
+	if x != nil {
+		return x.ClaimedAt
+	}
+	return ""
+}
+
+func (x *HistoryEvent) GetPreviousHash() string {
+	if x != nil {
+		return x.PreviousHash
+	}
+	return ""
+}
+
+type Organisation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OrgId string   `protobuf:"bytes,1,opt,name=org_id,json=orgId,proto3" json:"org_id,omitempty"`
+	Name  string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Type  string   `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Keys  []string `protobuf:"bytes,4,rep,name=keys,proto3" json:"keys,omitempty"`
+}
+
+func (x *Organisation) Reset() {
+	*x = Organisation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_wine_label_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Organisation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Organisation) ProtoMessage() {}
+
+func (x *Organisation) ProtoReflect() protoreflect.Message {
+	mi := &file_wine_label_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Organisation.ProtoReflect.Descriptor instead.
+func (*Organisation) Descriptor() ([]byte, []int) {
+	return file_wine_label_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Organisation) GetOrgId() string {
+	if x != nil {
+		return x.OrgId
+	}
+	return ""
+}
+
+func (x *Organisation) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Organisation) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Organisation) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+type KeyRecord struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PublicKey string `protobuf:"bytes,1,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	OrgId     string `protobuf:"bytes,2,opt,name=org_id,json=orgId,proto3" json:"org_id,omitempty"`
+}
+
+func (x *KeyRecord) Reset() {
+	*x = KeyRecord{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_wine_label_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KeyRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeyRecord) ProtoMessage() {}
+
+func (x *KeyRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_wine_label_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeyRecord.ProtoReflect.Descriptor instead.
+func (*KeyRecord) Descriptor() ([]byte, []int) {
+	return file_wine_label_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *KeyRecord) GetPublicKey() string {
+	if x != nil {
+		return x.PublicKey
+	}
+	return ""
+}
+
+func (x *KeyRecord) GetOrgId() string {
+	if x != nil {
+		return x.OrgId
+	}
+	return ""
+}
+
+type Lot struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LotId       string      `protobuf:"bytes,1,opt,name=lot_id,json=lotId,proto3" json:"lot_id,omitempty"`
+	Vintage     uint64      `protobuf:"varint,2,opt,name=vintage,proto3" json:"vintage,omitempty"`
+	Varietals   []*Varietal `protobuf:"bytes,3,rep,name=varietals,proto3" json:"varietals,omitempty"`
+	Appellation string      `protobuf:"bytes,4,opt,name=appellation,proto3" json:"appellation,omitempty"`
+	Producer    string      `protobuf:"bytes,5,opt,name=producer,proto3" json:"producer,omitempty"`
+	BottleCount uint64      `protobuf:"varint,6,opt,name=bottle_count,json=bottleCount,proto3" json:"bottle_count,omitempty"`
+	LabelCount  uint64      `protobuf:"varint,7,opt,name=label_count,json=labelCount,proto3" json:"label_count,omitempty"`
+	Recall      *Recall     `protobuf:"bytes,8,opt,name=recall,proto3" json:"recall,omitempty"`
+}
+
+func (x *Lot) Reset() {
+	*x = Lot{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_wine_label_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Lot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Lot) ProtoMessage() {}
+
+func (x *Lot) ProtoReflect() protoreflect.Message {
+	mi := &file_wine_label_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Lot.ProtoReflect.Descriptor instead.
+func (*Lot) Descriptor() ([]byte, []int) {
+	return file_wine_label_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Lot) GetLotId() string {
+	if x != nil {
+		return x.LotId
+	}
+	return ""
+}
+
+func (x *Lot) GetVintage() uint64 {
+	if x != nil {
+		return x.Vintage
+	}
+	return 0
+}
+
+func (x *Lot) GetVarietals() []*Varietal {
+	if x != nil {
+		return x.Varietals
+	}
+	return nil
+}
+
+func (x *Lot) GetAppellation() string {
+	if x != nil {
+		return x.Appellation
+	}
+	return ""
+}
+
+func (x *Lot) GetProducer() string {
+	if x != nil {
+		return x.Producer
+	}
+	return ""
+}
+
+func (x *Lot) GetBottleCount() uint64 {
+	if x != nil {
+		return x.BottleCount
+	}
+	return 0
+}
+
+func (x *Lot) GetLabelCount() uint64 {
+	if x != nil {
+		return x.LabelCount
+	}
+	return 0
+}
+
+func (x *Lot) GetRecall() *Recall {
+	if x != nil {
+		return x.Recall
+	}
+	return nil
+}
+
+type Recall struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Reason     string `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+	RecalledAt string `protobuf:"bytes,2,opt,name=recalled_at,json=recalledAt,proto3" json:"recalled_at,omitempty"`
+	RecalledBy string `protobuf:"bytes,3,opt,name=recalled_by,json=recalledBy,proto3" json:"recalled_by,omitempty"`
+}
+
+func (x *Recall) Reset() {
+	*x = Recall{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_wine_label_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Recall) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Recall) ProtoMessage() {}
+
+func (x *Recall) ProtoReflect() protoreflect.Message {
+	mi := &file_wine_label_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Recall.ProtoReflect.Descriptor instead.
+func (*Recall) Descriptor() ([]byte, []int) {
+	return file_wine_label_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Recall) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Recall) GetRecalledAt() string {
+	if x != nil {
+		return x.RecalledAt
+	}
+	return ""
+}
+
+func (x *Recall) GetRecalledBy() string {
+	if x != nil {
+		return x.RecalledBy
+	}
+	return ""
+}
+
+type IndexEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	WineLabelId string `protobuf:"bytes,1,opt,name=wine_label_id,json=wineLabelId,proto3" json:"wine_label_id,omitempty"`
+}
+
+func (x *IndexEntry) Reset() {
+	*x = IndexEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_wine_label_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IndexEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IndexEntry) ProtoMessage() {}
+
+func (x *IndexEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_wine_label_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IndexEntry.ProtoReflect.Descriptor instead.
+func (*IndexEntry) Descriptor() ([]byte, []int) {
+	return file_wine_label_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *IndexEntry) GetWineLabelId() string {
+	if x != nil {
+		return x.WineLabelId
+	}
+	return ""
+}
+
+var File_wine_label_proto protoreflect.FileDescriptor
+
+var file_wine_label_proto_rawDesc = []byte{
+	0x0a, 0x10, 0x77, 0x69, 0x6e, 0x65, 0x5f, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x09, 0x77, 0x69, 0x6e, 0x65, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x22, 0x38, 0x0a,
+	0x08, 0x45, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x22, 0xf5, 0x01, 0x0a, 0x07, 0x50, 0x61, 0x79, 0x6c,
+	0x6f, 0x61, 0x64, 0x12, 0x22, 0x0a, 0x0d, 0x77, 0x69, 0x6e, 0x65, 0x5f, 0x6c, 0x61, 0x62, 0x65,
+	0x6c, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x77, 0x69, 0x6e, 0x65,
+	0x4c, 0x61, 0x62, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x72, 0x69, 0x6e, 0x74,
+	0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x72, 0x69,
+	0x6e, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x6c, 0x6f, 0x6e, 0x67, 0x69, 0x74,
+	0x75, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6c, 0x6f, 0x6e, 0x67, 0x69,
+	0x74, 0x75, 0x64, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x61, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x61, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65,
+	0x12, 0x10, 0x0a, 0x03, 0x6c, 0x6f, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6c,
+	0x6f, 0x74, 0x12, 0x2e, 0x0a, 0x06, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x77, 0x69, 0x6e, 0x65, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x2e, 0x4c,
+	0x61, 0x62, 0x65, 0x6c, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x06, 0x73, 0x65, 0x63, 0x72,
+	0x65, 0x74, 0x12, 0x2b, 0x0a, 0x05, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x15, 0x2e, 0x77, 0x69, 0x6e, 0x65, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x2e, 0x50, 0x72,
+	0x69, 0x6e, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x05, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x22,
+	0x72, 0x0a, 0x0a, 0x50, 0x72, 0x69, 0x6e, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x0a,
+	0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x69, 0x6d,
+	0x65, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x66,
+	0x61, 0x63, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66,
+	0x61, 0x63, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x6f, 0x70, 0x65, 0x72, 0x61,
+	0x74, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6f, 0x70, 0x65, 0x72, 0x61,
+	0x74, 0x6f, 0x72, 0x22, 0x35, 0x0a, 0x0b, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x53, 0x65, 0x63, 0x72,
+	0x65, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x61, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x73, 0x61, 0x6c, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x22, 0x5f, 0x0a, 0x0a, 0x4f, 0x72,
+	0x67, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x15, 0x0a, 0x06, 0x6f, 0x72, 0x67, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6f, 0x72, 0x67, 0x49, 0x64, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6b, 0x65, 0x79, 0x73, 0x18,
+	0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x65, 0x79, 0x73, 0x22, 0xf1, 0x02, 0x0a, 0x10,
+	0x57, 0x69, 0x6e, 0x65, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64,
+	0x12, 0x2c, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x12, 0x2e, 0x77, 0x69, 0x6e, 0x65, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x2e, 0x50, 0x61,
+	0x79, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x12,
+	0x0a, 0x04, 0x76, 0x65, 0x72, 0x62, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x76, 0x65,
+	0x72, 0x62, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74,
+	0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x6c, 0x61, 0x69, 0x6d, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x6c, 0x61, 0x69, 0x6d, 0x65, 0x64, 0x41, 0x74, 0x12,
+	0x39, 0x0a, 0x0c, 0x6f, 0x72, 0x67, 0x61, 0x6e, 0x69, 0x73, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x77, 0x69, 0x6e, 0x65, 0x6c, 0x61, 0x62, 0x65,
+	0x6c, 0x2e, 0x4f, 0x72, 0x67, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x0c, 0x6f, 0x72,
+	0x67, 0x61, 0x6e, 0x69, 0x73, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2a, 0x0a, 0x04, 0x6d, 0x69,
+	0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x77, 0x69, 0x6e, 0x65, 0x6c,
+	0x61, 0x62, 0x65, 0x6c, 0x2e, 0x4d, 0x69, 0x6e, 0x74, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64,
+	0x52, 0x04, 0x6d, 0x69, 0x6e, 0x74, 0x12, 0x36, 0x0a, 0x0b, 0x6c, 0x6f, 0x74, 0x5f, 0x64, 0x65,
+	0x74, 0x61, 0x69, 0x6c, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x77, 0x69,
+	0x6e, 0x65, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x2e, 0x4c, 0x6f, 0x74, 0x50, 0x61, 0x79, 0x6c, 0x6f,
+	0x61, 0x64, 0x52, 0x0a, 0x6c, 0x6f, 0x74, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x12, 0x16,
+	0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x27, 0x0a, 0x0f, 0x72, 0x65, 0x76, 0x65, 0x61, 0x6c,
+	0x65, 0x64, 0x5f, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0e, 0x72, 0x65, 0x76, 0x65, 0x61, 0x6c, 0x65, 0x64, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x22,
+	0xe7, 0x03, 0x0a, 0x0b, 0x4d, 0x69, 0x6e, 0x74, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12,
+	0x1b, 0x0a, 0x09, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x08, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x49, 0x64, 0x73, 0x12, 0x16, 0x0a, 0x06,
+	0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x72,
+	0x65, 0x66, 0x69, 0x78, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x69, 0x72, 0x73, 0x74, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x05, 0x66, 0x69, 0x72, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x12, 0x16, 0x0a, 0x06, 0x64, 0x69, 0x67, 0x69, 0x74, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x06, 0x64, 0x69, 0x67, 0x69, 0x74, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72, 0x69, 0x6e,
+	0x74, 0x65, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x72, 0x69, 0x6e, 0x74,
+	0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x61, 0x63, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x61, 0x63, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x1d,
+	0x0a, 0x0a, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x1c, 0x0a,
+	0x09, 0x6c, 0x6f, 0x6e, 0x67, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x6c, 0x6f, 0x6e, 0x67, 0x69, 0x74, 0x75, 0x64, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x6c,
+	0x61, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c,
+	0x61, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6c, 0x6f, 0x74, 0x18, 0x0b,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6c, 0x6f, 0x74, 0x12, 0x3d, 0x0a, 0x07, 0x73, 0x65, 0x63,
+	0x72, 0x65, 0x74, 0x73, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x77, 0x69, 0x6e,
+	0x65, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x2e, 0x4d, 0x69, 0x6e, 0x74, 0x50, 0x61, 0x79, 0x6c, 0x6f,
+	0x61, 0x64, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x07, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x73, 0x12, 0x2b, 0x0a, 0x05, 0x70, 0x72, 0x69, 0x6e,
+	0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x77, 0x69, 0x6e, 0x65, 0x6c, 0x61,
+	0x62, 0x65, 0x6c, 0x2e, 0x50, 0x72, 0x69, 0x6e, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x05,
+	0x70, 0x72, 0x69, 0x6e, 0x74, 0x1a, 0x52, 0x0a, 0x0c, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x73,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2c, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x77, 0x69, 0x6e, 0x65, 0x6c, 0x61, 0x62,
+	0x65, 0x6c, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x3a, 0x0a, 0x08, 0x56, 0x61, 0x72,
+	0x69, 0x65, 0x74, 0x61, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x67, 0x72, 0x61, 0x70, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x67, 0x72, 0x61, 0x70, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x70,
+	0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x70, 0x65,
+	0x72, 0x63, 0x65, 0x6e, 0x74, 0x22, 0xb5, 0x01, 0x0a, 0x0a, 0x4c, 0x6f, 0x74, 0x50, 0x61, 0x79,
+	0x6c, 0x6f, 0x61, 0x64, 0x12, 0x15, 0x0a, 0x06, 0x6c, 0x6f, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x6f, 0x74, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x76,
+	0x69, 0x6e, 0x74, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x76, 0x69,
+	0x6e, 0x74, 0x61, 0x67, 0x65, 0x12, 0x31, 0x0a, 0x09, 0x76, 0x61, 0x72, 0x69, 0x65, 0x74, 0x61,
+	0x6c, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x77, 0x69, 0x6e, 0x65, 0x6c,
+	0x61, 0x62, 0x65, 0x6c, 0x2e, 0x56, 0x61, 0x72, 0x69, 0x65, 0x74, 0x61, 0x6c, 0x52, 0x09, 0x76,
+	0x61, 0x72, 0x69, 0x65, 0x74, 0x61, 0x6c, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x61, 0x70, 0x70, 0x65,
+	0x6c, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x61,
+	0x70, 0x70, 0x65, 0x6c, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x21, 0x0a, 0x0c, 0x62, 0x6f,
+	0x74, 0x74, 0x6c, 0x65, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x0b, 0x62, 0x6f, 0x74, 0x74, 0x6c, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x44, 0x0a,
+	0x08, 0x47, 0x65, 0x6f, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x61, 0x74,
+	0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x6c, 0x61, 0x74,
+	0x69, 0x74, 0x75, 0x64, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6c, 0x6f, 0x6e, 0x67, 0x69, 0x74, 0x75,
+	0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x6c, 0x6f, 0x6e, 0x67, 0x69, 0x74,
+	0x75, 0x64, 0x65, 0x22, 0xb6, 0x05, 0x0a, 0x0b, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x52, 0x65, 0x63,
+	0x6f, 0x72, 0x64, 0x12, 0x22, 0x0a, 0x0d, 0x77, 0x69, 0x6e, 0x65, 0x5f, 0x6c, 0x61, 0x62, 0x65,
+	0x6c, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x77, 0x69, 0x6e, 0x65,
+	0x4c, 0x61, 0x62, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x72, 0x69, 0x6e, 0x74,
+	0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x72, 0x69,
+	0x6e, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x2f, 0x0a, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x77, 0x69, 0x6e, 0x65, 0x6c,
+	0x61, 0x62, 0x65, 0x6c, 0x2e, 0x47, 0x65, 0x6f, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x08, 0x70,
+	0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12,
+	0x14, 0x0a, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x6f, 0x77, 0x6e, 0x65, 0x72, 0x12, 0x23, 0x0a, 0x0d, 0x70, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67,
+	0x5f, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x70, 0x65,
+	0x6e, 0x64, 0x69, 0x6e, 0x67, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x12, 0x25, 0x0a, 0x0e, 0x68, 0x69,
+	0x73, 0x74, 0x6f, 0x72, 0x79, 0x5f, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x0d, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x4c, 0x65, 0x6e, 0x67, 0x74,
+	0x68, 0x12, 0x21, 0x0a, 0x0c, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x5f, 0x68, 0x65, 0x61,
+	0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79,
+	0x48, 0x65, 0x61, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x18,
+	0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x12, 0x1a,
+	0x0a, 0x08, 0x66, 0x61, 0x63, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x66, 0x61, 0x63, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6c, 0x6f,
+	0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6c, 0x6f, 0x74, 0x12, 0x1d, 0x0a, 0x0a,
+	0x73, 0x63, 0x61, 0x6e, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x09, 0x73, 0x63, 0x61, 0x6e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x2c, 0x0a, 0x09, 0x6c,
+	0x61, 0x73, 0x74, 0x5f, 0x73, 0x63, 0x61, 0x6e, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f,
+	0x2e, 0x77, 0x69, 0x6e, 0x65, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x2e, 0x53, 0x63, 0x61, 0x6e, 0x52,
+	0x08, 0x6c, 0x61, 0x73, 0x74, 0x53, 0x63, 0x61, 0x6e, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x63, 0x61,
+	0x6e, 0x5f, 0x72, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x0e, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x0b, 0x73, 0x63, 0x61, 0x6e, 0x52, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1e, 0x0a, 0x0a,
+	0x73, 0x75, 0x73, 0x70, 0x69, 0x63, 0x69, 0x6f, 0x75, 0x73, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0a, 0x73, 0x75, 0x73, 0x70, 0x69, 0x63, 0x69, 0x6f, 0x75, 0x73, 0x12, 0x2b, 0x0a, 0x11,
+	0x73, 0x75, 0x73, 0x70, 0x69, 0x63, 0x69, 0x6f, 0x75, 0x73, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f,
+	0x6e, 0x18, 0x10, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x73, 0x75, 0x73, 0x70, 0x69, 0x63, 0x69,
+	0x6f, 0x75, 0x73, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x2e, 0x0a, 0x06, 0x73, 0x65, 0x63,
+	0x72, 0x65, 0x74, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x77, 0x69, 0x6e, 0x65,
+	0x6c, 0x61, 0x62, 0x65, 0x6c, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x53, 0x65, 0x63, 0x72, 0x65,
+	0x74, 0x52, 0x06, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x26, 0x0a, 0x05, 0x63, 0x6c, 0x61,
+	0x69, 0x6d, 0x18, 0x12, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x77, 0x69, 0x6e, 0x65, 0x6c,
+	0x61, 0x62, 0x65, 0x6c, 0x2e, 0x43, 0x6c, 0x61, 0x69, 0x6d, 0x52, 0x05, 0x63, 0x6c, 0x61, 0x69,
+	0x6d, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18,
+	0x13, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x54, 0x69, 0x6d, 0x65,
+	0x12, 0x1a, 0x0a, 0x08, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x14, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x22, 0x42, 0x0a, 0x05,
+	0x43, 0x6c, 0x61, 0x69, 0x6d, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x6c, 0x61, 0x69, 0x6d, 0x61, 0x6e,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x6c, 0x61, 0x69, 0x6d, 0x61, 0x6e,
+	0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x6c, 0x61, 0x69, 0x6d, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x6c, 0x61, 0x69, 0x6d, 0x65, 0x64, 0x41, 0x74,
+	0x22, 0x70, 0x0a, 0x04, 0x53, 0x63, 0x61, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x63, 0x61, 0x6e,
+	0x6e, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x63, 0x61, 0x6e, 0x6e,
+	0x65, 0x72, 0x12, 0x2f, 0x0a, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x77, 0x69, 0x6e, 0x65, 0x6c, 0x61, 0x62, 0x65, 0x6c,
+	0x2e, 0x47, 0x65, 0x6f, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x63, 0x61, 0x6e, 0x6e, 0x65, 0x64, 0x5f, 0x61,
+	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x63, 0x61, 0x6e, 0x6e, 0x65, 0x64,
+	0x41, 0x74, 0x22, 0xae, 0x02, 0x0a, 0x0c, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x12, 0x22, 0x0a, 0x0d, 0x77, 0x69, 0x6e, 0x65, 0x5f, 0x6c, 0x61, 0x62, 0x65,
+	0x6c, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x77, 0x69, 0x6e, 0x65,
+	0x4c, 0x61, 0x62, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x71, 0x75, 0x65,
+	0x6e, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x73, 0x65, 0x71, 0x75, 0x65,
+	0x6e, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x76, 0x65, 0x72, 0x62, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x76, 0x65, 0x72, 0x62, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x69, 0x67, 0x6e, 0x65,
+	0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x12,
+	0x1a, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2f, 0x0a, 0x08, 0x70,
+	0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e,
+	0x77, 0x69, 0x6e, 0x65, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x2e, 0x47, 0x65, 0x6f, 0x50, 0x6f, 0x69,
+	0x6e, 0x74, 0x52, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x21, 0x0a, 0x0c,
+	0x68, 0x61, 0x73, 0x5f, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0b, 0x68, 0x61, 0x73, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x1d, 0x0a, 0x0a, 0x63, 0x6c, 0x61, 0x69, 0x6d, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x6c, 0x61, 0x69, 0x6d, 0x65, 0x64, 0x41, 0x74, 0x12, 0x23,
+	0x0a, 0x0d, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18,
+	0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x48,
+	0x61, 0x73, 0x68, 0x22, 0x61, 0x0a, 0x0c, 0x4f, 0x72, 0x67, 0x61, 0x6e, 0x69, 0x73, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x15, 0x0a, 0x06, 0x6f, 0x72, 0x67, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x6f, 0x72, 0x67, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6b, 0x65, 0x79, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x04, 0x6b, 0x65, 0x79, 0x73, 0x22, 0x41, 0x0a, 0x09, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x63,
+	0x6f, 0x72, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x5f, 0x6b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b,
+	0x65, 0x79, 0x12, 0x15, 0x0a, 0x06, 0x6f, 0x72, 0x67, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x6f, 0x72, 0x67, 0x49, 0x64, 0x22, 0x96, 0x02, 0x0a, 0x03, 0x4c, 0x6f,
+	0x74, 0x12, 0x15, 0x0a, 0x06, 0x6c, 0x6f, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x6c, 0x6f, 0x74, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x69, 0x6e, 0x74,
+	0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x76, 0x69, 0x6e, 0x74, 0x61,
+	0x67, 0x65, 0x12, 0x31, 0x0a, 0x09, 0x76, 0x61, 0x72, 0x69, 0x65, 0x74, 0x61, 0x6c, 0x73, 0x18,
+	0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x77, 0x69, 0x6e, 0x65, 0x6c, 0x61, 0x62, 0x65,
+	0x6c, 0x2e, 0x56, 0x61, 0x72, 0x69, 0x65, 0x74, 0x61, 0x6c, 0x52, 0x09, 0x76, 0x61, 0x72, 0x69,
+	0x65, 0x74, 0x61, 0x6c, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x61, 0x70, 0x70, 0x65, 0x6c, 0x6c, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x61, 0x70, 0x70, 0x65,
+	0x6c, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x64, 0x75,
+	0x63, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x64, 0x75,
+	0x63, 0x65, 0x72, 0x12, 0x21, 0x0a, 0x0c, 0x62, 0x6f, 0x74, 0x74, 0x6c, 0x65, 0x5f, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x62, 0x6f, 0x74, 0x74, 0x6c,
+	0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x5f,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x6c, 0x61, 0x62,
+	0x65, 0x6c, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x29, 0x0a, 0x06, 0x72, 0x65, 0x63, 0x61, 0x6c,
+	0x6c, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x77, 0x69, 0x6e, 0x65, 0x6c, 0x61,
+	0x62, 0x65, 0x6c, 0x2e, 0x52, 0x65, 0x63, 0x61, 0x6c, 0x6c, 0x52, 0x06, 0x72, 0x65, 0x63, 0x61,
+	0x6c, 0x6c, 0x22, 0x62, 0x0a, 0x06, 0x52, 0x65, 0x63, 0x61, 0x6c, 0x6c, 0x12, 0x16, 0x0a, 0x06,
+	0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65,
+	0x61, 0x73, 0x6f, 0x6e, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x65, 0x63, 0x61, 0x6c, 0x6c, 0x65, 0x64,
+	0x5f, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x65, 0x63, 0x61, 0x6c,
+	0x6c, 0x65, 0x64, 0x41, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x65, 0x63, 0x61, 0x6c, 0x6c, 0x65,
+	0x64, 0x5f, 0x62, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x65, 0x63, 0x61,
+	0x6c, 0x6c, 0x65, 0x64, 0x42, 0x79, 0x22, 0x30, 0x0a, 0x0a, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x22, 0x0a, 0x0d, 0x77, 0x69, 0x6e, 0x65, 0x5f, 0x6c, 0x61, 0x62,
+	0x65, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x77, 0x69, 0x6e,
+	0x65, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x49, 0x64, 0x42, 0x21, 0x5a, 0x1f, 0x77, 0x69, 0x6e, 0x65,
+	0x2d, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x2d, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
+}
+
+var (
+	file_wine_label_proto_rawDescOnce sync.Once
+	file_wine_label_proto_rawDescData = file_wine_label_proto_rawDesc
+)
+
+func file_wine_label_proto_rawDescGZIP() []byte {
+	file_wine_label_proto_rawDescOnce.Do(func() {
+		file_wine_label_proto_rawDescData = protoimpl.X.CompressGZIP(file_wine_label_proto_rawDescData)
+	})
+	return file_wine_label_proto_rawDescData
+}
+
+var file_wine_label_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
+var file_wine_label_proto_goTypes = []interface{}{
+	(*Envelope)(nil),         // 0: winelabel.Envelope
+	(*Payload)(nil),          // 1: winelabel.Payload
+	(*PrintEvent)(nil),       // 2: winelabel.PrintEvent
+	(*LabelSecret)(nil),      // 3: winelabel.LabelSecret
+	(*OrgPayload)(nil),       // 4: winelabel.OrgPayload
+	(*WineLabelPayload)(nil), // 5: winelabel.WineLabelPayload
+	(*MintPayload)(nil),      // 6: winelabel.MintPayload
+	(*Varietal)(nil),         // 7: winelabel.Varietal
+	(*LotPayload)(nil),       // 8: winelabel.LotPayload
+	(*GeoPoint)(nil),         // 9: winelabel.GeoPoint
+	(*LabelRecord)(nil),      // 10: winelabel.LabelRecord
+	(*Claim)(nil),            // 11: winelabel.Claim
+	(*Scan)(nil),             // 12: winelabel.Scan
+	(*HistoryEvent)(nil),     // 13: winelabel.HistoryEvent
+	(*Organisation)(nil),     // 14: winelabel.Organisation
+	(*KeyRecord)(nil),        // 15: winelabel.KeyRecord
+	(*Lot)(nil),              // 16: winelabel.Lot
+	(*Recall)(nil),           // 17: winelabel.Recall
+	(*IndexEntry)(nil),       // 18: winelabel.IndexEntry
+	nil,                      // 19: winelabel.MintPayload.SecretsEntry
+}
+var file_wine_label_proto_depIdxs = []int32{
+	3,  // 0: winelabel.Payload.secret:type_name -> winelabel.LabelSecret
+	2,  // 1: winelabel.Payload.print:type_name -> winelabel.PrintEvent
+	1,  // 2: winelabel.WineLabelPayload.payload:type_name -> winelabel.Payload
+	4,  // 3: winelabel.WineLabelPayload.organisation:type_name -> winelabel.OrgPayload
+	6,  // 4: winelabel.WineLabelPayload.mint:type_name -> winelabel.MintPayload
+	8,  // 5: winelabel.WineLabelPayload.lot_details:type_name -> winelabel.LotPayload
+	19, // 6: winelabel.MintPayload.secrets:type_name -> winelabel.MintPayload.SecretsEntry
+	2,  // 7: winelabel.MintPayload.print:type_name -> winelabel.PrintEvent
+	7,  // 8: winelabel.LotPayload.varietals:type_name -> winelabel.Varietal
+	9,  // 9: winelabel.LabelRecord.position:type_name -> winelabel.GeoPoint
+	12, // 10: winelabel.LabelRecord.last_scan:type_name -> winelabel.Scan
+	3,  // 11: winelabel.LabelRecord.secret:type_name -> winelabel.LabelSecret
+	11, // 12: winelabel.LabelRecord.claim:type_name -> winelabel.Claim
+	9,  // 13: winelabel.Scan.position:type_name -> winelabel.GeoPoint
+	9,  // 14: winelabel.HistoryEvent.position:type_name -> winelabel.GeoPoint
+	7,  // 15: winelabel.Lot.varietals:type_name -> winelabel.Varietal
+	17, // 16: winelabel.Lot.recall:type_name -> winelabel.Recall
+	3,  // 17: winelabel.MintPayload.SecretsEntry.value:type_name -> winelabel.LabelSecret
+	18, // [18:18] is the sub-list for method output_type
+	18, // [18:18] is the sub-list for method input_type
+	18, // [18:18] is the sub-list for extension type_name
+	18, // [18:18] is the sub-list for extension extendee
+	0,  // [0:18] is the sub-list for field type_name
+}
+
+func init() { file_wine_label_proto_init() }
+func file_wine_label_proto_init() {
+	if File_wine_label_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_wine_label_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Envelope); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_wine_label_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Payload); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_wine_label_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PrintEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_wine_label_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LabelSecret); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_wine_label_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OrgPayload); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_wine_label_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WineLabelPayload); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_wine_label_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MintPayload); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_wine_label_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Varietal); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_wine_label_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LotPayload); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_wine_label_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GeoPoint); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_wine_label_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LabelRecord); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_wine_label_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Claim); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_wine_label_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Scan); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_wine_label_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HistoryEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_wine_label_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Organisation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_wine_label_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KeyRecord); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_wine_label_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Lot); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_wine_label_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Recall); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_wine_label_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IndexEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_wine_label_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   20,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_wine_label_proto_goTypes,
+		DependencyIndexes: file_wine_label_proto_depIdxs,
+		MessageInfos:      file_wine_label_proto_msgTypes,
+	}.Build()
+	File_wine_label_proto = out.File
+	file_wine_label_proto_rawDesc = nil
+	file_wine_label_proto_goTypes = nil
+	file_wine_label_proto_depIdxs = nil
+}
@@ -0,0 +1,55 @@
+package protocol
+
+import (
+	"fmt"
+	"time"
+)
+
+// DEFAULT_MAX_CLOCK_SKEW is how far ahead of a client's clock a print time
+// may be, see CheckClock.
+const DEFAULT_MAX_CLOCK_SKEW time.Duration = 5 * time.Minute
+
+// PrintEvent describes the printing of a label set by VERB_SET; where it
+// was printed is given by the payload's coordinates. It supersedes the
+// free-form PrintedAt, which remains as a description of the location.
+type PrintEvent struct {
+	// Time is when the label was printed, as an RFC 3339 timestamp.
+	Time string
+	// Printer is the ID of the printing device and Facility the ID of the
+	// site it stands at.
+	Printer  string
+	Facility string
+	// Operator identifies who ran the print.
+	Operator string
+}
+
+// IsSet reports whether a payload carries a print event.
+func (self PrintEvent) IsSet() bool {
+	return self != PrintEvent{}
+}
+
+// Check validates a print event: every field is required, and the print
+// time must be an RFC 3339 timestamp. It does not look at a clock, since
+// every validator must come to the same result.
+func (self PrintEvent) Check() error {
+	if _, err := time.Parse(time.RFC3339, self.Time); err != nil {
+		return fmt.Errorf("Invalid print time %q: %v", self.Time, err)
+	}
+	if self.Printer == "" || self.Facility == "" || self.Operator == "" {
+		return fmt.Errorf("Print event must name the printer, facility and operator")
+	}
+	return nil
+}
+
+// CheckClock checks that the print time is at most skew ahead of now.
+// Clients check it against their own clock before submitting a print.
+func (self PrintEvent) CheckClock(now time.Time, skew time.Duration) error {
+	printedAt, err := time.Parse(time.RFC3339, self.Time)
+	if err != nil {
+		return fmt.Errorf("Invalid print time %q: %v", self.Time, err)
+	}
+	if printedAt.After(now.Add(skew)) {
+		return fmt.Errorf("Print time %v is more than %v in the future", self.Time, skew)
+	}
+	return nil
+}
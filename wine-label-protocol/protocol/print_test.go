@@ -0,0 +1,54 @@
+package protocol
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPrintEventCheck(t *testing.T) {
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	valid := PrintEvent{Time: "2020-01-02T03:04:05Z", Printer: "press-1", Facility: "cellar", Operator: "alice"}
+	cases := []struct {
+		name  string
+		event PrintEvent
+		valid bool
+	}{
+		{"now", valid, true},
+		{"past", PrintEvent{"2019-12-31T23:00:00+01:00", "press-1", "cellar", "alice"}, true},
+		{"within skew", PrintEvent{"2020-01-02T03:08:05Z", "press-1", "cellar", "alice"}, true},
+		{"beyond skew", PrintEvent{"2020-01-02T03:10:00Z", "press-1", "cellar", "alice"}, false},
+		{"other zone beyond skew", PrintEvent{"2020-01-02T04:10:00+01:00", "press-1", "cellar", "alice"}, false},
+		{"no time", PrintEvent{"", "press-1", "cellar", "alice"}, false},
+		{"free-form time", PrintEvent{"yesterday", "press-1", "cellar", "alice"}, false},
+		{"no printer", PrintEvent{"2020-01-02T03:04:05Z", "", "cellar", "alice"}, false},
+		{"no facility", PrintEvent{"2020-01-02T03:04:05Z", "press-1", "", "alice"}, false},
+		{"no operator", PrintEvent{"2020-01-02T03:04:05Z", "press-1", "cellar", ""}, false},
+	}
+	for _, c := range cases {
+		err := c.event.Check()
+		if err == nil {
+			err = c.event.CheckClock(now, 5*time.Minute)
+		}
+		if (err == nil) != c.valid {
+			t.Errorf("%s: Check = %v, want valid %v", c.name, err, c.valid)
+		}
+	}
+	if err := valid.CheckClock(now.Add(-time.Second), 0); err == nil {
+		t.Error("CheckClock accepted a print time ahead of the clock with no skew")
+	}
+	if err := (PrintEvent{"2030-01-01T00:00:00Z", "press-1", "cellar", "alice"}).Check(); err != nil {
+		t.Errorf("Check looked at the clock: %v", err)
+	}
+}
+
+func TestNewLabelRecordPrintEvent(t *testing.T) {
+	event := PrintEvent{Time: "2020-01-02T03:04:05Z", Printer: "press-1", Facility: "cellar", Operator: "alice"}
+	record, err := NewLabelRecord(Payload{WineLabelID: "125", Lattitude: "0", Longitude: "0", Print: event}, "02ab")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.PrintTime != event.Time || record.Printer != event.Printer ||
+		record.Facility != event.Facility || record.Operator != event.Operator {
+		t.Errorf("NewLabelRecord = %+v, want the print event %+v", record, event)
+	}
+}
@@ -0,0 +1,522 @@
+package protocol
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	protoV2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"wine-label-protocol/protocol/pb"
+)
+
+// Encoding selects how payloads and records are serialised.
+type Encoding string
+
+const (
+	ENCODING_CBOR     Encoding = "cbor"
+	ENCODING_PROTOBUF Encoding = "protobuf"
+)
+
+// ENCODINGS lists every encoding a processor accepts.
+var ENCODINGS = []Encoding{ENCODING_CBOR, ENCODING_PROTOBUF}
+
+// PROTOBUF_MARKER starts all protobuf encoded data. A zero byte is a CBOR
+// integer, never the map that CBOR payloads and records begin with, so the
+// marker tells the two encodings apart. See pb/wine_label.proto.
+var PROTOBUF_MARKER = []byte("\x00WLP")
+
+// EncodePayloadAs encodes a transaction payload for the given family
+// version in the given encoding. Family version 1.0 is CBOR only.
+func EncodePayloadAs(familyVersion string, encoding Encoding, payload WineLabelPayload) ([]byte, error) {
+	switch encoding {
+	case ENCODING_CBOR:
+		return EncodePayload(familyVersion, payload)
+	case ENCODING_PROTOBUF:
+		if familyVersion != FAMILY_VERSION_2 {
+			return nil, fmt.Errorf("Family version %v does not support %v encoding", familyVersion, encoding)
+		}
+		return encodeProtobuf(payloadToProto(payload))
+	}
+	return nil, fmt.Errorf("Unsupported encoding: %v", encoding)
+}
+
+// EncodeRecordAs encodes a state record in the current schema version and
+// the given encoding.
+func EncodeRecordAs(encoding Encoding, record interface{}) ([]byte, error) {
+	switch encoding {
+	case ENCODING_CBOR:
+		return EncodeRecord(record)
+	case ENCODING_PROTOBUF:
+		message, err := recordToProto(record)
+		if err != nil {
+			return nil, err
+		}
+		return encodeProtobuf(message)
+	}
+	return nil, fmt.Errorf("Unsupported encoding: %v", encoding)
+}
+
+// IsProtobuf reports whether data is protobuf encoded.
+func IsProtobuf(data []byte) bool {
+	return bytes.HasPrefix(data, PROTOBUF_MARKER)
+}
+
+// protobufEncoder orders map entries so that protobuf encoding, like the
+// CBOR encoding, is deterministic.
+var protobufEncoder = protoV2.MarshalOptions{Deterministic: true}
+
+func encodeProtobuf(message proto.Message) ([]byte, error) {
+	body, err := protobufEncoder.Marshal(proto.MessageV2(message))
+	if err != nil {
+		return nil, err
+	}
+	envelope, err := protobufEncoder.Marshal(&pb.Envelope{Version: SCHEMA_VERSION, Body: body})
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte{}, PROTOBUF_MARKER...), envelope...), nil
+}
+
+// decodeProtobuf unwraps protobuf encoded data and decodes its body into
+// message. Like DecodeCBOR it rejects fields it does not know.
+func decodeProtobuf(data []byte, message proto.Message) error {
+	var envelope pb.Envelope
+	if err := unmarshalProtobuf(data[len(PROTOBUF_MARKER):], &envelope); err != nil {
+		return err
+	}
+	if envelope.Version == 0 {
+		return fmt.Errorf("Protobuf envelope has no schema version")
+	}
+	if err := checkSchemaVersion(envelope.Version); err != nil {
+		return err
+	}
+	return unmarshalProtobuf(envelope.Body, message)
+}
+
+func unmarshalProtobuf(data []byte, message proto.Message) error {
+	if err := proto.Unmarshal(data, message); err != nil {
+		return fmt.Errorf("malformed protobuf: %v", err)
+	}
+	if unknown := findUnknownFields(proto.MessageReflect(message)); unknown != "" {
+		return fmt.Errorf("malformed protobuf: unknown field in %v", unknown)
+	}
+	return nil
+}
+
+// findUnknownFields returns the name of the first message in message, or
+// nested in it, that holds fields not in its definition.
+func findUnknownFields(message protoreflect.Message) protoreflect.FullName {
+	if len(message.GetUnknown()) > 0 {
+		return message.Descriptor().FullName()
+	}
+	var unknown protoreflect.FullName
+	message.Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		switch {
+		case field.IsList():
+			if field.Message() != nil {
+				list := value.List()
+				for i := 0; i < list.Len() && unknown == ""; i++ {
+					unknown = findUnknownFields(list.Get(i).Message())
+				}
+			}
+		case field.IsMap():
+			if field.MapValue().Message() != nil {
+				value.Map().Range(func(_ protoreflect.MapKey, entry protoreflect.Value) bool {
+					unknown = findUnknownFields(entry.Message())
+					return unknown == ""
+				})
+			}
+		case field.Message() != nil:
+			unknown = findUnknownFields(value.Message())
+		}
+		return unknown == ""
+	})
+	return unknown
+}
+
+func decodeProtobufPayload(data []byte) (WineLabelPayload, error) {
+	var message pb.WineLabelPayload
+	if err := decodeProtobuf(data, &message); err != nil {
+		return WineLabelPayload{}, err
+	}
+	return payloadFromProto(&message), nil
+}
+
+// decodeProtobufRecord decodes protobuf encoded state into pointer, which
+// must point to one of the state record types.
+func decodeProtobufRecord(data []byte, pointer interface{}) error {
+	switch record := pointer.(type) {
+	case *LabelRecord:
+		var message pb.LabelRecord
+		if err := decodeProtobuf(data, &message); err != nil {
+			return err
+		}
+		*record = LabelRecord{
+			WineLabelID:   message.WineLabelId,
+			PrintedAt:     message.PrintedAt,
+			Position:      geoPointFromProto(message.Position),
+			Status:        LabelStatus(message.Status),
+			Owner:         message.Owner,
+			PendingOwner:  message.PendingOwner,
+			HistoryLength: message.HistoryLength,
+			HistoryHead:   message.HistoryHead,
+			Printer:       message.Printer,
+			Facility:      message.Facility,
+			PrintTime:     message.PrintTime,
+			Operator:      message.Operator,
+			Lot:           message.Lot,
+			ScanCount:     message.ScanCount,
+			LastScan: Scan{
+				Scanner:   message.LastScan.GetScanner(),
+				Position:  geoPointFromProto(message.LastScan.GetPosition()),
+				ScannedAt: message.LastScan.GetScannedAt(),
+			},
+			ScanRegions:      message.ScanRegions,
+			Suspicious:       message.Suspicious,
+			SuspiciousReason: message.SuspiciousReason,
+			Secret:           secretFromProto(message.Secret),
+			Claim:            claimFromProto(message.Claim),
+		}
+	case *HistoryEvent:
+		var message pb.HistoryEvent
+		if err := decodeProtobuf(data, &message); err != nil {
+			return err
+		}
+		*record = HistoryEvent{
+			WineLabelID:  message.WineLabelId,
+			Sequence:     message.Sequence,
+			Verb:         message.Verb,
+			Signer:       message.Signer,
+			Location:     message.Location,
+			Position:     geoPointFromProto(message.Position),
+			HasPosition:  message.HasPosition,
+			ClaimedAt:    message.ClaimedAt,
+			PreviousHash: message.PreviousHash,
+		}
+	case *Organisation:
+		var message pb.Organisation
+		if err := decodeProtobuf(data, &message); err != nil {
+			return err
+		}
+		*record = Organisation{
+			OrgID: message.OrgId,
+			Name:  message.Name,
+			Type:  message.Type,
+			Keys:  message.Keys,
+		}
+	case *KeyRecord:
+		var message pb.KeyRecord
+		if err := decodeProtobuf(data, &message); err != nil {
+			return err
+		}
+		*record = KeyRecord{PublicKey: message.PublicKey, OrgID: message.OrgId}
+	case *Lot:
+		var message pb.Lot
+		if err := decodeProtobuf(data, &message); err != nil {
+			return err
+		}
+		*record = Lot{
+			LotID:       message.LotId,
+			Vintage:     message.Vintage,
+			Varietals:   varietalsFromProto(message.Varietals),
+			Appellation: message.Appellation,
+			Producer:    message.Producer,
+			BottleCount: message.BottleCount,
+			LabelCount:  message.LabelCount,
+			Recall:      recallFromProto(message.Recall),
+		}
+	case *IndexEntry:
+		var message pb.IndexEntry
+		if err := decodeProtobuf(data, &message); err != nil {
+			return err
+		}
+		*record = IndexEntry{WineLabelID: message.WineLabelId}
+	default:
+		return fmt.Errorf("No protobuf encoding for %T", pointer)
+	}
+	return nil
+}
+
+func recordToProto(record interface{}) (proto.Message, error) {
+	switch record := record.(type) {
+	case LabelRecord:
+		return &pb.LabelRecord{
+			WineLabelId:   record.WineLabelID,
+			PrintedAt:     record.PrintedAt,
+			Position:      geoPointToProto(record.Position),
+			Status:        string(record.Status),
+			Owner:         record.Owner,
+			PendingOwner:  record.PendingOwner,
+			HistoryLength: record.HistoryLength,
+			HistoryHead:   record.HistoryHead,
+			Printer:       record.Printer,
+			Facility:      record.Facility,
+			PrintTime:     record.PrintTime,
+			Operator:      record.Operator,
+			Lot:           record.Lot,
+			ScanCount:     record.ScanCount,
+			LastScan: &pb.Scan{
+				Scanner:   record.LastScan.Scanner,
+				Position:  geoPointToProto(record.LastScan.Position),
+				ScannedAt: record.LastScan.ScannedAt,
+			},
+			ScanRegions:      record.ScanRegions,
+			Suspicious:       record.Suspicious,
+			SuspiciousReason: record.SuspiciousReason,
+			Secret:           secretToProto(record.Secret),
+			Claim:            claimToProto(record.Claim),
+		}, nil
+	case HistoryEvent:
+		return &pb.HistoryEvent{
+			WineLabelId:  record.WineLabelID,
+			Sequence:     record.Sequence,
+			Verb:         record.Verb,
+			Signer:       record.Signer,
+			Location:     record.Location,
+			Position:     geoPointToProto(record.Position),
+			HasPosition:  record.HasPosition,
+			ClaimedAt:    record.ClaimedAt,
+			PreviousHash: record.PreviousHash,
+		}, nil
+	case Organisation:
+		return &pb.Organisation{
+			OrgId: record.OrgID,
+			Name:  record.Name,
+			Type:  record.Type,
+			Keys:  record.Keys,
+		}, nil
+	case KeyRecord:
+		return &pb.KeyRecord{PublicKey: record.PublicKey, OrgId: record.OrgID}, nil
+	case Lot:
+		return &pb.Lot{
+			LotId:       record.LotID,
+			Vintage:     record.Vintage,
+			Varietals:   varietalsToProto(record.Varietals),
+			Appellation: record.Appellation,
+			Producer:    record.Producer,
+			BottleCount: record.BottleCount,
+			LabelCount:  record.LabelCount,
+			Recall:      recallToProto(record.Recall),
+		}, nil
+	case IndexEntry:
+		return &pb.IndexEntry{WineLabelId: record.WineLabelID}, nil
+	}
+	return nil, fmt.Errorf("No protobuf encoding for %T", record)
+}
+
+func payloadToProto(payload WineLabelPayload) *pb.WineLabelPayload {
+	return &pb.WineLabelPayload{
+		Payload: &pb.Payload{
+			WineLabelId: payload.WineLabelID,
+			PrintedAt:   payload.PrintedAt,
+			Longitude:   payload.Longitude,
+			Latitude:    payload.Lattitude,
+			Lot:         payload.Lot,
+			Secret:      secretToProto(payload.Secret),
+			Print:       printToProto(payload.Print),
+		},
+		Verb:      payload.Verb,
+		Recipient: payload.Recipient,
+		ClaimedAt: payload.ClaimedAt,
+		Reason:    payload.Reason,
+
+		RevealedSecret: payload.RevealedSecret,
+		Organisation: &pb.OrgPayload{
+			OrgId: payload.Organisation.OrgID,
+			Name:  payload.Organisation.Name,
+			Type:  payload.Organisation.Type,
+			Keys:  payload.Organisation.Keys,
+		},
+		Mint: &pb.MintPayload{
+			LabelIds:  payload.Mint.LabelIDs,
+			Prefix:    payload.Mint.Prefix,
+			First:     payload.Mint.First,
+			Count:     payload.Mint.Count,
+			Digits:    payload.Mint.Digits,
+			Print:     printToProto(payload.Mint.Print),
+			Printer:   payload.Mint.Printer,
+			Facility:  payload.Mint.Facility,
+			PrintedAt: payload.Mint.PrintedAt,
+			Longitude: payload.Mint.Longitude,
+			Latitude:  payload.Mint.Lattitude,
+			Lot:       payload.Mint.Lot,
+			Secrets:   secretsToProto(payload.Mint.Secrets),
+		},
+		LotDetails: &pb.LotPayload{
+			LotId:       payload.LotDetails.LotID,
+			Vintage:     payload.LotDetails.Vintage,
+			Varietals:   varietalsToProto(payload.LotDetails.Varietals),
+			Appellation: payload.LotDetails.Appellation,
+			BottleCount: payload.LotDetails.BottleCount,
+		},
+	}
+}
+
+func payloadFromProto(message *pb.WineLabelPayload) WineLabelPayload {
+	payload := message.GetPayload()
+	org := message.GetOrganisation()
+	mint := message.GetMint()
+	lot := message.GetLotDetails()
+	return WineLabelPayload{
+		Payload: Payload{
+			WineLabelID: payload.GetWineLabelId(),
+			PrintedAt:   payload.GetPrintedAt(),
+			Longitude:   payload.GetLongitude(),
+			Lattitude:   payload.GetLatitude(),
+			Lot:         payload.GetLot(),
+			Secret:      secretFromProto(payload.GetSecret()),
+			Print:       printFromProto(payload.GetPrint()),
+		},
+		Verb:      message.Verb,
+		Recipient: message.Recipient,
+		ClaimedAt: message.ClaimedAt,
+		Reason:    message.Reason,
+
+		RevealedSecret: message.RevealedSecret,
+		Organisation: OrgPayload{
+			OrgID: org.GetOrgId(),
+			Name:  org.GetName(),
+			Type:  org.GetType(),
+			Keys:  org.GetKeys(),
+		},
+		Mint: MintPayload{
+			LabelIDs:  mint.GetLabelIds(),
+			Prefix:    mint.GetPrefix(),
+			First:     mint.GetFirst(),
+			Count:     mint.GetCount(),
+			Digits:    mint.GetDigits(),
+			Print:     printFromProto(mint.GetPrint()),
+			Printer:   mint.GetPrinter(),
+			Facility:  mint.GetFacility(),
+			PrintedAt: mint.GetPrintedAt(),
+			Longitude: mint.GetLongitude(),
+			Lattitude: mint.GetLatitude(),
+			Lot:       mint.GetLot(),
+			Secrets:   secretsFromProto(mint.GetSecrets()),
+		},
+		LotDetails: LotPayload{
+			LotID:       lot.GetLotId(),
+			Vintage:     lot.GetVintage(),
+			Varietals:   varietalsFromProto(lot.GetVarietals()),
+			Appellation: lot.GetAppellation(),
+			BottleCount: lot.GetBottleCount(),
+		},
+	}
+}
+
+func varietalsToProto(varietals []Varietal) []*pb.Varietal {
+	if varietals == nil {
+		return nil
+	}
+	messages := make([]*pb.Varietal, 0, len(varietals))
+	for _, varietal := range varietals {
+		messages = append(messages, &pb.Varietal{Grape: varietal.Grape, Percent: varietal.Percent})
+	}
+	return messages
+}
+
+func varietalsFromProto(messages []*pb.Varietal) []Varietal {
+	if messages == nil {
+		return nil
+	}
+	varietals := make([]Varietal, 0, len(messages))
+	for _, message := range messages {
+		varietals = append(varietals, Varietal{Grape: message.Grape, Percent: message.Percent})
+	}
+	return varietals
+}
+
+func printToProto(event PrintEvent) *pb.PrintEvent {
+	if !event.IsSet() {
+		return nil
+	}
+	return &pb.PrintEvent{
+		Time:     event.Time,
+		Printer:  event.Printer,
+		Facility: event.Facility,
+		Operator: event.Operator,
+	}
+}
+
+func printFromProto(message *pb.PrintEvent) PrintEvent {
+	return PrintEvent{
+		Time:     message.GetTime(),
+		Printer:  message.GetPrinter(),
+		Facility: message.GetFacility(),
+		Operator: message.GetOperator(),
+	}
+}
+
+func secretToProto(secret LabelSecret) *pb.LabelSecret {
+	if !secret.IsSet() {
+		return nil
+	}
+	return &pb.LabelSecret{Salt: secret.Salt, Hash: secret.Hash}
+}
+
+func secretFromProto(message *pb.LabelSecret) LabelSecret {
+	return LabelSecret{Salt: message.GetSalt(), Hash: message.GetHash()}
+}
+
+func secretsToProto(secrets map[string]LabelSecret) map[string]*pb.LabelSecret {
+	if secrets == nil {
+		return nil
+	}
+	messages := make(map[string]*pb.LabelSecret, len(secrets))
+	for id, secret := range secrets {
+		messages[id] = &pb.LabelSecret{Salt: secret.Salt, Hash: secret.Hash}
+	}
+	return messages
+}
+
+func secretsFromProto(messages map[string]*pb.LabelSecret) map[string]LabelSecret {
+	if messages == nil {
+		return nil
+	}
+	secrets := make(map[string]LabelSecret, len(messages))
+	for id, message := range messages {
+		secrets[id] = secretFromProto(message)
+	}
+	return secrets
+}
+
+func claimToProto(claim *Claim) *pb.Claim {
+	if claim == nil {
+		return nil
+	}
+	return &pb.Claim{Claimant: claim.Claimant, ClaimedAt: claim.ClaimedAt}
+}
+
+func claimFromProto(message *pb.Claim) *Claim {
+	if message == nil {
+		return nil
+	}
+	return &Claim{Claimant: message.Claimant, ClaimedAt: message.ClaimedAt}
+}
+
+func recallToProto(recall *Recall) *pb.Recall {
+	if recall == nil {
+		return nil
+	}
+	return &pb.Recall{Reason: recall.Reason, RecalledAt: recall.RecalledAt, RecalledBy: recall.RecalledBy}
+}
+
+func recallFromProto(message *pb.Recall) *Recall {
+	if message == nil {
+		return nil
+	}
+	return &Recall{Reason: message.Reason, RecalledAt: message.RecalledAt, RecalledBy: message.RecalledBy}
+}
+
+func geoPointToProto(point GeoPoint) *pb.GeoPoint {
+	return &pb.GeoPoint{Latitude: int64(point.Latitude), Longitude: int64(point.Longitude)}
+}
+
+func geoPointFromProto(point *pb.GeoPoint) GeoPoint {
+	return GeoPoint{
+		Latitude:  Latitude(point.GetLatitude()),
+		Longitude: Longitude(point.GetLongitude()),
+	}
+}
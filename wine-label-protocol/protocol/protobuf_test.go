@@ -0,0 +1,164 @@
+package protocol
+
+import (
+	"encoding/hex"
+	"reflect"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	"wine-label-protocol/protocol/pb"
+)
+
+func TestPayloadCrossEncoding(t *testing.T) {
+	payloads := []WineLabelPayload{
+		{Payload: Payload{WineLabelID: "125", PrintedAt: "loc", Longitude: "34.3", Lattitude: "23.2"}, Verb: VERB_SET, ClaimedAt: "2020-01-02T03:04:05Z"},
+		{Payload: Payload{WineLabelID: "125"}, Verb: VERB_OFFER, Recipient: "02ab"},
+		{Verb: VERB_REGISTER_ORG, Organisation: OrgPayload{"chateau", "Chateau", ORG_WINERY, []string{"02ab", "03cd"}}},
+		{Verb: VERB_MINT, Mint: MintPayload{Prefix: "W-", First: 1, Count: 10, Digits: 4, Lot: "lot-2019",
+			Print:   PrintEvent{"2020-01-02T03:04:05Z", "press-1", "cellar", "alice"},
+			Secrets: map[string]LabelSecret{"W-0001": {secretSalt, SecretHash(secretSalt, "scratch")}}}},
+		{Payload: Payload{WineLabelID: "125", Secret: LabelSecret{secretSalt, SecretHash(secretSalt, "scratch")}}, Verb: VERB_SET},
+		{Payload: Payload{WineLabelID: "125"}, Verb: VERB_CLAIM, RevealedSecret: "scratch"},
+	}
+	for _, payload := range payloads {
+		var decoded []WineLabelPayload
+		for _, encoding := range ENCODINGS {
+			data, err := EncodePayloadAs(FAMILY_VERSION_2, encoding, payload)
+			if err != nil {
+				t.Fatalf("EncodePayloadAs(%v): %v", encoding, err)
+			}
+			if IsProtobuf(data) != (encoding == ENCODING_PROTOBUF) {
+				t.Errorf("IsProtobuf(%v encoding) = %v", encoding, IsProtobuf(data))
+			}
+			result, err := DecodePayload(FAMILY_VERSION_2, data)
+			if err != nil {
+				t.Fatalf("DecodePayload(%v): %v", encoding, err)
+			}
+			decoded = append(decoded, result)
+		}
+		if !reflect.DeepEqual(decoded[0], decoded[1]) {
+			t.Errorf("CBOR decodes to %+v, protobuf to %+v", decoded[0], decoded[1])
+		}
+		if decoded[1].Payload != payload.Payload || decoded[1].Verb != payload.Verb ||
+			decoded[1].RevealedSecret != payload.RevealedSecret || !reflect.DeepEqual(decoded[1].Mint.Secrets, payload.Mint.Secrets) ||
+			decoded[1].Mint.Print != payload.Mint.Print ||
+			decoded[1].Recipient != payload.Recipient || decoded[1].Organisation.OrgID != payload.Organisation.OrgID {
+			t.Errorf("DecodePayload = %+v, want %+v", decoded[1], payload)
+		}
+	}
+}
+
+func TestProtobufPayloadGolden(t *testing.T) {
+	data, err := EncodePayloadAs(FAMILY_VERSION_2, ENCODING_PROTOBUF,
+		WineLabelPayload{Payload: Payload{WineLabelID: "125"}, Verb: VERB_DELETE})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "00574c50080212150a050a03313235120664656c6574652a0032003a00"
+	if hex.EncodeToString(data) != want {
+		t.Errorf("EncodePayloadAs = %x, want %s", data, want)
+	}
+}
+
+func TestProtobufNeedsFamilyVersion2(t *testing.T) {
+	if _, err := EncodePayloadAs(FAMILY_VERSION_1, ENCODING_PROTOBUF, WineLabelPayload{}); err == nil {
+		t.Error("EncodePayloadAs accepted protobuf for family version 1.0")
+	}
+	data, _ := EncodePayloadAs(FAMILY_VERSION_2, ENCODING_PROTOBUF, WineLabelPayload{Verb: VERB_SET})
+	if _, err := DecodePayload(FAMILY_VERSION_1, data); err == nil {
+		t.Error("DecodePayload accepted protobuf for family version 1.0")
+	}
+}
+
+func TestRecordCrossEncoding(t *testing.T) {
+	position := GeoPoint{Latitude: 44837789, Longitude: -579180}
+	records := []struct {
+		record  interface{}
+		pointer func() interface{}
+	}{
+		{LabelRecord{"125", "loc", position, STATUS_SHIPPED, "02ab", "03cd", 3, "abc", "press-1", "cellar", "2020-01-01T03:04:05Z", "alice", "lot-2019",
+			2, Scan{"04ef", position, "2020-01-02T03:04:05Z"}, []string{"c3a", "d12"}, true, "scanned in more than 5 regions",
+			LabelSecret{secretSalt, SecretHash(secretSalt, "scratch")}, &Claim{"04ef", "2020-01-03T03:04:05Z"}},
+			func() interface{} { return &LabelRecord{} }},
+		{HistoryEvent{"125", 2, VERB_SHIP, "02ab", "loc", position, true, "2020-01-02T03:04:05Z", "abc"},
+			func() interface{} { return &HistoryEvent{} }},
+		{Organisation{"chateau", "Chateau", ORG_WINERY, []string{"02ab"}},
+			func() interface{} { return &Organisation{} }},
+		{KeyRecord{"02ab", "chateau"},
+			func() interface{} { return &KeyRecord{} }},
+		{Lot{"lot-2019", 2019, []Varietal{{"Merlot", 60}, {"Cabernet Franc", 40}}, "Saint-Emilion", "chateau", 6000, 12,
+			&Recall{"contamination", "2020-01-02T03:04:05Z", "02ab"}},
+			func() interface{} { return &Lot{} }},
+		{IndexEntry{"125"},
+			func() interface{} { return &IndexEntry{} }},
+	}
+	for _, c := range records {
+		for _, encoding := range ENCODINGS {
+			data, err := EncodeRecordAs(encoding, c.record)
+			if err != nil {
+				t.Fatalf("EncodeRecordAs(%v, %T): %v", encoding, c.record, err)
+			}
+			pointer := c.pointer()
+			if err := DecodeRecord(data, pointer); err != nil {
+				t.Fatalf("DecodeRecord(%v, %T): %v", encoding, c.record, err)
+			}
+			got := reflect.ValueOf(pointer).Elem().Interface()
+			if !reflect.DeepEqual(got, c.record) {
+				t.Errorf("%v round trip = %+v, want %+v", encoding, got, c.record)
+			}
+		}
+	}
+}
+
+func TestDecodeLabelRecordProtobuf(t *testing.T) {
+	want := LabelRecord{WineLabelID: "125", Status: STATUS_SOLD, Owner: "02ab"}
+	data, err := EncodeRecordAs(ENCODING_PROTOBUF, want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record, err := DecodeLabelRecord(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(record, want) {
+		t.Errorf("DecodeLabelRecord = %+v, want %+v", record, want)
+	}
+}
+
+func TestDecodeProtobufMalformed(t *testing.T) {
+	cases := map[string][]byte{
+		"truncated":    append(append([]byte{}, PROTOBUF_MARKER...), 0x12, 0x05),
+		"no version":   PROTOBUF_MARKER,
+		"newer schema": append(append([]byte{}, PROTOBUF_MARKER...), 0x08, byte(SCHEMA_VERSION+1)),
+		// Field 15 is not part of Envelope.
+		"unknown field": append(append([]byte{}, PROTOBUF_MARKER...), 0x08, byte(SCHEMA_VERSION), 0x78, 0x01),
+	}
+	for name, data := range cases {
+		if _, err := DecodePayload(FAMILY_VERSION_2, data); err == nil {
+			t.Errorf("%s: DecodePayload accepted %x", name, data)
+		}
+		var record LabelRecord
+		if err := DecodeRecord(data, &record); err == nil {
+			t.Errorf("%s: DecodeRecord accepted %x", name, data)
+		}
+	}
+}
+
+func TestFindUnknownFieldsInCollections(t *testing.T) {
+	// Field 15 is not part of Varietal or LabelSecret.
+	unknown := []byte{0x78, 0x01}
+	varietal := &pb.Varietal{Grape: "Merlot"}
+	varietal.ProtoReflect().SetUnknown(unknown)
+	secret := &pb.LabelSecret{}
+	secret.ProtoReflect().SetUnknown(unknown)
+	cases := map[string]proto.Message{
+		"list": &pb.LotPayload{LotId: "L1", Varietals: []*pb.Varietal{{Grape: "Syrah"}, varietal}},
+		"map":  &pb.MintPayload{Secrets: map[string]*pb.LabelSecret{"125": secret}},
+	}
+	for name, message := range cases {
+		if got := findUnknownFields(proto.MessageReflect(message)); got == "" {
+			t.Errorf("%s: unknown field not found", name)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+// Package protocol holds the wire format shared by the wine-label
+// transaction processor and its client: payload types, verbs, CBOR
+// encoding and the address layout of the wine-label namespace.
+//
+// Decoding is strict: data larger than MAX_PAYLOAD_SIZE, unknown or
+// duplicate fields, fields of the wrong type and trailing bytes are all
+// rejected. Encoding is canonical, so that every processor writes
+// byte-identical state for the same transaction.
+package protocol
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+const (
+	// String literals
+	FAMILY_NAME string = "wine-label"
+	// FAMILY_VERSION is the version clients send by default.
+	FAMILY_VERSION string = FAMILY_VERSION_2
+
+	// Verbs
+	VERB_SET    string = "set"
+	VERB_DELETE string = "delete"
+	// Custody transfer verbs: the owner offers a label to a recipient, who
+	// must accept it before ownership moves. Either side may cancel.
+	VERB_OFFER  string = "offer"
+	VERB_ACCEPT string = "accept"
+	VERB_CANCEL string = "cancel"
+
+	// MAX_PAYLOAD_SIZE bounds the encoded size of a transaction payload.
+	MAX_PAYLOAD_SIZE int = 64 * 1024
+	// MAX_NESTING bounds how deeply CBOR arrays and maps may nest.
+	MAX_NESTING int = 8
+)
+
+type WineLabelPayload struct {
+	// The embedded payload is encoded as a nested "Payload" map, as the
+	// original clients sent it, rather than inlined.
+	Payload `cbor:"Payload"`
+	Verb    string
+	// Recipient is the public key a label is offered to by VERB_OFFER.
+	Recipient string
+	// ClaimedAt is when the signer says the action took place, recorded in
+	// the label's history.
+	ClaimedAt string
+	// Organisation is set by the registry verbs instead of a label.
+	Organisation OrgPayload
+	// Mint is set by VERB_MINT instead of a single label.
+	Mint MintPayload
+	// LotDetails is set by VERB_CREATE_LOT, and names the lot VERB_RECALL
+	// recalls.
+	LotDetails LotPayload
+	// Reason is why VERB_RECALL recalls a lot.
+	Reason string
+	// RevealedSecret is the scratch-off secret VERB_CLAIM reveals.
+	RevealedSecret string
+}
+
+type Payload struct {
+	WineLabelID string
+	// PrintedAt is a free-form description of where the label was printed.
+	PrintedAt string
+	Longitude string
+	Lattitude string
+	// Lot is the ID of the lot the label is for, if known.
+	Lot string
+	// Secret optionally commits the label to a scratch-off secret.
+	Secret LabelSecret
+	// Print is the structured print event. VERB_SET requires one under
+	// FAMILY_VERSION_2.
+	Print PrintEvent
+}
+
+// LabelRecord is the state stored at a label address. It replaces storing
+// the raw Payload: coordinates are validated and typed, and the record is
+// built from named fields only.
+type LabelRecord struct {
+	WineLabelID string
+	PrintedAt   string
+	Position    GeoPoint
+	Status      LabelStatus
+	// Owner is the public key of the label's current custodian, the only
+	// key allowed to change the label.
+	Owner string
+	// PendingOwner is the recipient of an open custody offer, if any.
+	PendingOwner string
+	// HistoryLength and HistoryHead locate the end of the label's history
+	// chain: the number of events and the hash of the last one.
+	HistoryLength uint64
+	HistoryHead   string
+	// Printer and Facility are recorded from a label's print event, or when
+	// it is minted as part of a roll; PrintTime and Operator from its print
+	// event.
+	Printer   string
+	Facility  string
+	PrintTime string
+	Operator  string
+	// Lot is the ID of the lot the label is attached to, if any.
+	Lot string
+	// ScanCount counts the label's scans, LastScan is the latest and
+	// ScanRegions the geohash cells they came from, see RecordScan.
+	ScanCount   uint64
+	LastScan    Scan
+	ScanRegions []string
+	// Suspicious is set for good once scans suggest the label was cloned.
+	Suspicious       bool
+	SuspiciousReason string
+	// Secret is the label's scratch-off commitment, if it has one, and
+	// Claim is set once the secret has been revealed by VERB_CLAIM.
+	Secret LabelSecret
+	Claim  *Claim
+}
+
+// NewLabelRecord validates the coordinates of a set payload and builds the
+// record to store for a newly printed label owned by owner.
+func NewLabelRecord(payload Payload, owner string) (LabelRecord, error) {
+	position, err := ParseGeoPoint(payload.Lattitude, payload.Longitude)
+	if err != nil {
+		return LabelRecord{}, err
+	}
+	if payload.Secret.IsSet() {
+		if err := payload.Secret.Check(); err != nil {
+			return LabelRecord{}, err
+		}
+	}
+	return LabelRecord{
+		WineLabelID: payload.WineLabelID,
+		PrintedAt:   payload.PrintedAt,
+		Position:    position,
+		Status:      STATUS_PRINTED,
+		Owner:       owner,
+		Lot:         payload.Lot,
+		Secret:      payload.Secret,
+		Printer:     payload.Print.Printer,
+		Facility:    payload.Print.Facility,
+		PrintTime:   payload.Print.Time,
+		Operator:    payload.Print.Operator,
+	}, nil
+}
+
+var (
+	// cborEncoder writes canonical CBOR (RFC 7049 section 3.9): map keys
+	// sorted length first, shortest integer forms and definite lengths.
+	cborEncoder = mustEncMode(cbor.CanonicalEncOptions())
+	cborDecoder = mustDecMode(cbor.DecOptions{
+		DupMapKey:         cbor.DupMapKeyEnforcedAPF,
+		ExtraReturnErrors: cbor.ExtraDecErrorUnknownField,
+		IndefLength:       cbor.IndefLengthForbidden,
+		TagsMd:            cbor.TagsForbidden,
+		MaxNestedLevels:   MAX_NESTING,
+	})
+	// envelopeProbe reads the Version field of data that may or may not be
+	// an Envelope, ignoring any other fields.
+	envelopeProbe = mustDecMode(cbor.DecOptions{
+		DupMapKey:       cbor.DupMapKeyEnforcedAPF,
+		IndefLength:     cbor.IndefLengthForbidden,
+		TagsMd:          cbor.TagsForbidden,
+		MaxNestedLevels: MAX_NESTING,
+	})
+)
+
+func mustEncMode(options cbor.EncOptions) cbor.EncMode {
+	mode, err := options.EncMode()
+	if err != nil {
+		panic(err)
+	}
+	return mode
+}
+
+func mustDecMode(options cbor.DecOptions) cbor.DecMode {
+	mode, err := options.DecMode()
+	if err != nil {
+		panic(err)
+	}
+	return mode
+}
+
+// EncodeCBOR encodes value as canonical CBOR.
+func EncodeCBOR(value interface{}) ([]byte, error) {
+	return cborEncoder.Marshal(value)
+}
+
+// DecodeCBOR strictly decodes data, a single CBOR item, into pointer.
+func DecodeCBOR(data []byte, pointer interface{}) error {
+	return decodeCBORWith(cborDecoder, data, pointer)
+}
+
+func decodeCBORWith(mode cbor.DecMode, data []byte, pointer interface{}) error {
+	decoder := mode.NewDecoder(bytes.NewReader(data))
+	if err := decoder.Decode(pointer); err != nil {
+		return fmt.Errorf("malformed CBOR: %v", err)
+	}
+	if decoder.NumBytesRead() != len(data) {
+		return fmt.Errorf("malformed CBOR: %d bytes of trailing data",
+			len(data)-decoder.NumBytesRead())
+	}
+	return nil
+}
+
+func Hexdigest(str string) string {
+	hash := sha512.New()
+	hash.Write([]byte(str))
+	hashBytes := hash.Sum(nil)
+	return strings.ToLower(hex.EncodeToString(hashBytes))
+}
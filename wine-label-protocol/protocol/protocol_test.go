@@ -0,0 +1,202 @@
+package protocol
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// Golden vectors pin the wire format: the client must produce exactly these
+// bytes and addresses, and the handler must read them back unchanged.
+
+func TestNamespace(t *testing.T) {
+	if ns := NewNamespace(FAMILY_NAME); ns != "b25576" {
+		t.Errorf("NewNamespace(%q) = %q, want %q", FAMILY_NAME, ns, "b25576")
+	}
+}
+
+func TestLabelAddress(t *testing.T) {
+	ns := NewNamespace(FAMILY_NAME)
+	cases := []struct {
+		labelID string
+		address string
+	}{
+		{"125", "b2557600b7953ae09943b8bec668936bd8bda735a8262a1cbe3b6cb372d755f708c380"},
+		{"W-0001", "b255760097e47bb5418b5897400808e151f30c1ccb50893a18a3b778eec1a039311de6"},
+	}
+	for _, c := range cases {
+		address := ns.LabelAddress(c.labelID)
+		if address != c.address {
+			t.Errorf("LabelAddress(%q) = %s, want %s", c.labelID, address, c.address)
+		}
+		if len(address) != ADDRESS_LENGTH {
+			t.Errorf("LabelAddress(%q) has length %d, want %d", c.labelID, len(address), ADDRESS_LENGTH)
+		}
+	}
+}
+
+// TestLegacyLabelAddress checks the address against the one the original
+// processor computed for label 125.
+func TestLegacyLabelAddress(t *testing.T) {
+	address := NewNamespace(FAMILY_NAME).LegacyLabelAddress("125")
+	if want := "b255763b4acb0724dafb7e531bdcf65c7da688ca9f1701091d0f0a72269d400514618a"; address != want {
+		t.Errorf("LegacyLabelAddress(%q) = %s, want %s", "125", address, want)
+	}
+}
+
+func TestHistoryAddress(t *testing.T) {
+	ns := NewNamespace(FAMILY_NAME)
+	cases := []struct {
+		sequence uint64
+		address  string
+	}{
+		{0, "b2557601b7953ae09943b8bec668936bd8bda735a8262a1cbe3b6c0000000000000000"},
+		{1, "b2557601b7953ae09943b8bec668936bd8bda735a8262a1cbe3b6c0000000000000001"},
+		{255, "b2557601b7953ae09943b8bec668936bd8bda735a8262a1cbe3b6c00000000000000ff"},
+	}
+	for _, c := range cases {
+		address := ns.HistoryAddress("125", c.sequence)
+		if address != c.address {
+			t.Errorf("HistoryAddress(%q, %d) = %s, want %s", "125", c.sequence, address, c.address)
+		}
+		if !strings.HasPrefix(address, ns.HistoryPrefix("125")) {
+			t.Errorf("HistoryAddress(%q, %d) is not under HistoryPrefix", "125", c.sequence)
+		}
+	}
+}
+
+// The 1.0 encoding is a bare CBOR payload. Payloads are encoded
+// canonically; legacy holds the same payload as the first clients encoded
+// it, with fields in declaration order, which must still decode.
+// TestPayloadGolden checks the current 1.0 encoding of each payload, and
+// that the bytes sent by older 1.0 clients still decode: baseline is what
+// the original client sent, before the family had offers or an
+// organisation registry, and earlier is what clients sent before the
+// payload gained mint, lot, print and secret fields.
+func TestPayloadGolden(t *testing.T) {
+	cases := []struct {
+		name     string
+		payload  WineLabelPayload
+		encoded  string
+		earlier  string
+		baseline string
+	}{
+		{
+			"set",
+			WineLabelPayload{Payload: Payload{WineLabelID: "125", PrintedAt: "loc", Longitude: "34.3", Lattitude: "23.2"}, Verb: VERB_SET},
+			"a9644d696e74ad634c6f746065436f756e740065466972737400655072696e74a46454696d6560675072696e7465726068466163696c69747960684f70657261746f726066446967697473006650726566697860675072696e746572606753656372657473f668466163696c69747960684c6162656c494473f6694c617474697475646560694c6f6e67697475646560695072696e74656441746064566572626373657466526561736f6e60675061796c6f6164a7634c6f7460655072696e74a46454696d6560675072696e7465726068466163696c69747960684f70657261746f726066536563726574a26448617368606453616c7460694c61747469747564656432332e32694c6f6e6769747564656433342e33695072696e7465644174636c6f636b57696e654c6162656c49446331323569436c61696d656441746069526563697069656e74606a4c6f7444657461696c73a5654c6f744944606756696e746167650069566172696574616c73f66b417070656c6c6174696f6e606b426f74746c65436f756e74006c4f7267616e69736174696f6ea4644b657973f6644e616d6560645479706560654f72674944606e52657665616c656453656372657460",
+			"a5675061796c6f6164a46b57696e654c6162656c494463313235695072696e7465644174636c6f63694c6f6e6769747564656433342e33694c61747469747564656432332e3264566572626373657469526563697069656e746069436c61696d65644174606c4f7267616e69736174696f6ea4654f7267494460644e616d6560645479706560644b65797380",
+			"a2675061796c6f6164a46b57696e654c6162656c494463313235695072696e7465644174636c6f63694c6f6e6769747564656433342e33694c61747469747564656432332e32645665726263736574",
+		},
+		{
+			"delete",
+			WineLabelPayload{Payload: Payload{WineLabelID: "125"}, Verb: VERB_DELETE},
+			"a9644d696e74ad634c6f746065436f756e740065466972737400655072696e74a46454696d6560675072696e7465726068466163696c69747960684f70657261746f726066446967697473006650726566697860675072696e746572606753656372657473f668466163696c69747960684c6162656c494473f6694c617474697475646560694c6f6e67697475646560695072696e74656441746064566572626664656c65746566526561736f6e60675061796c6f6164a7634c6f7460655072696e74a46454696d6560675072696e7465726068466163696c69747960684f70657261746f726066536563726574a26448617368606453616c7460694c617474697475646560694c6f6e67697475646560695072696e7465644174606b57696e654c6162656c49446331323569436c61696d656441746069526563697069656e74606a4c6f7444657461696c73a5654c6f744944606756696e746167650069566172696574616c73f66b417070656c6c6174696f6e606b426f74746c65436f756e74006c4f7267616e69736174696f6ea4644b657973f6644e616d6560645479706560654f72674944606e52657665616c656453656372657460",
+			"a5675061796c6f6164a46b57696e654c6162656c494463313235695072696e746564417460694c6f6e67697475646560694c61747469747564656064566572626664656c65746569526563697069656e746069436c61696d65644174606c4f7267616e69736174696f6ea4654f7267494460644e616d6560645479706560644b65797380",
+			"a2675061796c6f6164a46b57696e654c6162656c494463313235695072696e746564417460694c6f6e67697475646560694c61747469747564656064566572626664656c657465",
+		},
+		{
+			"offer",
+			WineLabelPayload{Payload: Payload{WineLabelID: "125"}, Verb: VERB_OFFER, Recipient: "02ab"},
+			"a9644d696e74ad634c6f746065436f756e740065466972737400655072696e74a46454696d6560675072696e7465726068466163696c69747960684f70657261746f726066446967697473006650726566697860675072696e746572606753656372657473f668466163696c69747960684c6162656c494473f6694c617474697475646560694c6f6e67697475646560695072696e7465644174606456657262656f6666657266526561736f6e60675061796c6f6164a7634c6f7460655072696e74a46454696d6560675072696e7465726068466163696c69747960684f70657261746f726066536563726574a26448617368606453616c7460694c617474697475646560694c6f6e67697475646560695072696e7465644174606b57696e654c6162656c49446331323569436c61696d656441746069526563697069656e7464303261626a4c6f7444657461696c73a5654c6f744944606756696e746167650069566172696574616c73f66b417070656c6c6174696f6e606b426f74746c65436f756e74006c4f7267616e69736174696f6ea4644b657973f6644e616d6560645479706560654f72674944606e52657665616c656453656372657460",
+			"a5675061796c6f6164a46b57696e654c6162656c494463313235695072696e746564417460694c6f6e67697475646560694c6174746974756465606456657262656f6666657269526563697069656e74643032616269436c61696d65644174606c4f7267616e69736174696f6ea4654f7267494460644e616d6560645479706560644b65797380",
+			"",
+		},
+		{
+			"register-org",
+			WineLabelPayload{
+				Verb:         VERB_REGISTER_ORG,
+				Organisation: OrgPayload{"chateau", "Chateau", ORG_WINERY, []string{"02ab"}},
+			},
+			"a9644d696e74ad634c6f746065436f756e740065466972737400655072696e74a46454696d6560675072696e7465726068466163696c69747960684f70657261746f726066446967697473006650726566697860675072696e746572606753656372657473f668466163696c69747960684c6162656c494473f6694c617474697475646560694c6f6e67697475646560695072696e74656441746064566572626c72656769737465722d6f726766526561736f6e60675061796c6f6164a7634c6f7460655072696e74a46454696d6560675072696e7465726068466163696c69747960684f70657261746f726066536563726574a26448617368606453616c7460694c617474697475646560694c6f6e67697475646560695072696e7465644174606b57696e654c6162656c49446069436c61696d656441746069526563697069656e74606a4c6f7444657461696c73a5654c6f744944606756696e746167650069566172696574616c73f66b417070656c6c6174696f6e606b426f74746c65436f756e74006c4f7267616e69736174696f6ea4644b657973816430326162644e616d65674368617465617564547970656677696e657279654f7267494467636861746561756e52657665616c656453656372657460",
+			"a5675061796c6f6164a46b57696e654c6162656c494460695072696e746564417460694c6f6e67697475646560694c61747469747564656064566572626c72656769737465722d6f726769526563697069656e746069436c61696d65644174606c4f7267616e69736174696f6ea4654f726749446763686174656175644e616d65674368617465617564547970656677696e657279644b657973816430326162",
+			"",
+		},
+	}
+	for _, c := range cases {
+		encoded, err := EncodePayload(FAMILY_VERSION_1, c.payload)
+		if err != nil {
+			t.Fatalf("%s: EncodePayload: %v", c.name, err)
+		}
+		if hex.EncodeToString(encoded) != c.encoded {
+			t.Errorf("%s: EncodePayload = %x, want %s", c.name, encoded, c.encoded)
+		}
+
+		for _, golden := range []string{c.encoded, c.earlier, c.baseline} {
+			if golden == "" {
+				continue
+			}
+			data, _ := hex.DecodeString(golden)
+			decoded, err := DecodePayload(FAMILY_VERSION_1, data)
+			if err != nil {
+				t.Errorf("%s: DecodePayload: %v", c.name, err)
+				continue
+			}
+			if decoded.Payload != c.payload.Payload || decoded.Verb != c.payload.Verb ||
+				decoded.Recipient != c.payload.Recipient {
+				t.Errorf("%s: DecodePayload = %+v, want %+v", c.name, decoded, c.payload)
+			}
+		}
+
+	}
+}
+
+func TestDecodeCBORStrict(t *testing.T) {
+	cases := map[string]string{
+		"truncated": "a267",
+		// {"Verb": "set", "Verb": "set"}
+		"duplicate field": "a2645665726263736574645665726263736574",
+		// {"Colour": "red"}
+		"unknown field": "a166436f6c6f757263726564",
+		// {"Verb": 1}
+		"wrong type": "a1645665726201",
+		// {"Verb": "set"} followed by a stray byte
+		"trailing data": "a164566572626373657400",
+		// {_ "Verb": "set"}, indefinite length
+		"indefinite map": "bf645665726263736574ff",
+		// {"Verb": 1("set")}, tagged
+		"tag": "a16456657262c163736574",
+	}
+	for name, data := range cases {
+		encoded, _ := hex.DecodeString(data)
+		var decoded WineLabelPayload
+		if err := DecodeCBOR(encoded, &decoded); err == nil {
+			t.Errorf("%s: DecodeCBOR accepted %s", name, data)
+		}
+	}
+	valid, _ := hex.DecodeString("a1645665726263736574")
+	var decoded WineLabelPayload
+	if err := DecodeCBOR(valid, &decoded); err != nil || decoded.Verb != VERB_SET {
+		t.Errorf("DecodeCBOR(%x) = %+v, %v", valid, decoded, err)
+	}
+}
+
+func TestDecodePayloadSizeLimit(t *testing.T) {
+	payload := WineLabelPayload{Verb: VERB_SET, ClaimedAt: strings.Repeat("x", MAX_PAYLOAD_SIZE)}
+	for _, version := range FAMILY_VERSIONS {
+		data, err := EncodePayload(version, payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := DecodePayload(version, data); err == nil {
+			t.Errorf("DecodePayload(%v) accepted %d bytes", version, len(data))
+		}
+	}
+}
+
+func TestEncodeRecordCanonical(t *testing.T) {
+	record := LabelRecord{WineLabelID: "125", Status: STATUS_PRINTED, Owner: "02ab"}
+	first, _ := EncodeRecord(record)
+	var decoded LabelRecord
+	if err := DecodeRecord(first, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	second, _ := EncodeRecord(decoded)
+	if hex.EncodeToString(first) != hex.EncodeToString(second) {
+		t.Errorf("EncodeRecord is not canonical: %x then %x", first, second)
+	}
+	want := "a264426f647959013ab4634c6f746065436c61696df6654f776e6572643032616266536563726574a26448617368606453616c746066537461747573677072696e746564675072696e7465726068466163696c69747960684c6173745363616ea3675363616e6e65726068506f736974696f6ea2684c6174697475646500694c6f6e67697475646500695363616e6e6564417460684f70657261746f726068506f736974696f6ea2684c6174697475646500694c6f6e67697475646500695072696e7454696d6560695072696e746564417460695363616e436f756e74006a537573706963696f7573f46b486973746f727948656164606b5363616e526567696f6e73f66b57696e654c6162656c4944633132356c50656e64696e674f776e6572606d486973746f72794c656e6774680070537573706963696f7573526561736f6e606756657273696f6e02"
+	if hex.EncodeToString(first) != want {
+		t.Errorf("EncodeRecord = %x, want %s", first, want)
+	}
+}
@@ -0,0 +1,36 @@
+package protocol
+
+import (
+	"fmt"
+	"time"
+)
+
+// VERB_RECALL recalls every bottle of the lot named in the payload's
+// LotDetails, for the payload's Reason. Keys registered to the winery that
+// produced the lot may recall it, as may the keys listed in
+// REGULATORS_SETTING. The payload's ClaimedAt is the date of the recall.
+const VERB_RECALL string = "recall"
+
+// Recall records why and when a lot was recalled, and by whom.
+type Recall struct {
+	Reason     string
+	RecalledAt string
+	// RecalledBy is the public key that signed the recall.
+	RecalledBy string
+}
+
+// NewRecall validates a recall and builds the record to store on the lot.
+// recalledAt must be an RFC 3339 time.
+func NewRecall(reason string, recalledAt string, signer string) (Recall, error) {
+	if reason == "" {
+		return Recall{}, fmt.Errorf("A recall must give a reason")
+	}
+	if _, err := time.Parse(time.RFC3339, recalledAt); err != nil {
+		return Recall{}, fmt.Errorf("Invalid recall time %q: %v", recalledAt, err)
+	}
+	return Recall{Reason: reason, RecalledAt: recalledAt, RecalledBy: signer}, nil
+}
+
+func (self Recall) String() string {
+	return fmt.Sprintf("recalled on %v: %v", self.RecalledAt, self.Reason)
+}
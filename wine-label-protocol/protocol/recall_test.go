@@ -0,0 +1,41 @@
+package protocol
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewRecall(t *testing.T) {
+	recall, err := NewRecall("contamination", "2020-01-02T03:04:05Z", "02ab")
+	want := Recall{Reason: "contamination", RecalledAt: "2020-01-02T03:04:05Z", RecalledBy: "02ab"}
+	if err != nil || recall != want {
+		t.Errorf("NewRecall = %+v, %v, want %+v", recall, err, want)
+	}
+	invalid := []struct{ reason, recalledAt string }{
+		{"", "2020-01-02T03:04:05Z"},
+		{"contamination", ""},
+		{"contamination", "2 January 2020"},
+	}
+	for _, c := range invalid {
+		if _, err := NewRecall(c.reason, c.recalledAt, "02ab"); err == nil {
+			t.Errorf("NewRecall(%q, %q) accepted an invalid recall", c.reason, c.recalledAt)
+		}
+	}
+}
+
+func TestLotRecallRoundTrip(t *testing.T) {
+	for _, recall := range []*Recall{nil, {"contamination", "2020-01-02T03:04:05Z", "02ab"}} {
+		lot := Lot{LotID: "lot-2019", Producer: "chateau", BottleCount: 6000, Recall: recall}
+		data, err := EncodeRecord(lot)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded Lot
+		if err := DecodeRecord(data, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(decoded, lot) {
+			t.Errorf("DecodeRecord = %+v, want %+v", decoded, lot)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package protocol
+
+import (
+	"fmt"
+	"time"
+)
+
+// VERB_SCAN records that someone, typically a consumer checking a bottle,
+// scanned a label. Anyone may scan a label. Each scan is checked against
+// the ones before it for signs that the label has been cloned.
+const VERB_SCAN string = "scan"
+
+const (
+	// MAX_TRAVEL_SPEED_KMH is the fastest a label may travel between two
+	// scans, about the speed of an airliner.
+	MAX_TRAVEL_SPEED_KMH uint64 = 1000
+	// Scans closer together than MIN_TRAVEL_KM are never too fast, so that
+	// position error does not flag labels scanned twice in one place.
+	MIN_TRAVEL_KM uint64 = 50
+	// A label scanned in more than MAX_SCAN_REGIONS regions is suspicious.
+	// Regions are geohash cells of SCAN_REGION_DIGITS digits, about 600 by
+	// 300 km at the equator.
+	MAX_SCAN_REGIONS   int = 5
+	SCAN_REGION_DIGITS int = 3
+)
+
+// Scan is one scan of a label: who scanned it, where and when they say
+// they did. ScannedAt is an RFC 3339 time.
+type Scan struct {
+	Scanner   string
+	Position  GeoPoint
+	ScannedAt string
+}
+
+// RecordScan adds scan to the record and checks it against the previous
+// scan and the regions of all earlier ones. It returns why the label is
+// suspicious if this scan is the one that flags it, and "" otherwise; a
+// suspicious label stays flagged. Scans must arrive in order, so that a
+// signer cannot invent a journey by backdating its scans. The scan time is
+// not checked against a clock, since every validator must come to the same
+// result; the client stamps it with its own.
+func (self *LabelRecord) RecordScan(scan Scan) (string, error) {
+	scannedAt, err := time.Parse(time.RFC3339, scan.ScannedAt)
+	if err != nil {
+		return "", fmt.Errorf("Invalid scan time %q: %v", scan.ScannedAt, err)
+	}
+	reason := ""
+	if self.ScanCount > 0 {
+		previousAt, err := time.Parse(time.RFC3339, self.LastScan.ScannedAt)
+		if err == nil && scannedAt.Before(previousAt) {
+			return "", fmt.Errorf("Scan time %v is before the last scan at %v",
+				scan.ScannedAt, self.LastScan.ScannedAt)
+		}
+		if err == nil {
+			reason = impossibleTravel(self.LastScan.Position, scan.Position, scannedAt.Sub(previousAt))
+		}
+	}
+	region := GeoCell(scan.Position, SCAN_REGION_DIGITS)
+	known := false
+	for _, scanned := range self.ScanRegions {
+		known = known || scanned == region
+	}
+	// One region past the limit is enough to flag the label, so the list
+	// stops growing there.
+	if !known && len(self.ScanRegions) <= MAX_SCAN_REGIONS {
+		self.ScanRegions = append(self.ScanRegions, region)
+	}
+	if reason == "" && len(self.ScanRegions) > MAX_SCAN_REGIONS {
+		reason = fmt.Sprintf("scanned in more than %d regions", MAX_SCAN_REGIONS)
+	}
+	self.ScanCount++
+	self.LastScan = scan
+	if reason == "" || self.Suspicious {
+		return "", nil
+	}
+	self.Suspicious = true
+	self.SuspiciousReason = reason
+	return reason, nil
+}
+
+// impossibleTravel describes a journey of elapsed time if it is faster
+// than MAX_TRAVEL_SPEED_KMH. The distance and speed are computed in
+// integers, so every processor reaches the same verdict.
+func impossibleTravel(from GeoPoint, to GeoPoint, elapsed time.Duration) string {
+	km := ChordKm(from, to)
+	if km < MIN_TRAVEL_KM {
+		return ""
+	}
+	seconds := uint64(elapsed / time.Second)
+	if km*3600 <= MAX_TRAVEL_SPEED_KMH*seconds {
+		return ""
+	}
+	return fmt.Sprintf("scanned %d km apart within %v", km, time.Duration(seconds)*time.Second)
+}
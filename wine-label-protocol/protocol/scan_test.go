@@ -0,0 +1,102 @@
+package protocol
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRecordScan(t *testing.T) {
+	bordeaux := mustGeoPoint(t, "44.8378", "-0.5792")
+	pauillac := mustGeoPoint(t, "45.1989", "-0.7489")
+	shanghai := mustGeoPoint(t, "31.2304", "121.4737")
+
+	cases := []struct {
+		name       string
+		scans      []Scan
+		suspicious string
+	}{
+		{"single scan", []Scan{
+			{"02ab", bordeaux, "2020-01-02T03:04:05Z"},
+		}, ""},
+		{"same place", []Scan{
+			{"02ab", bordeaux, "2020-01-02T03:04:05Z"},
+			{"03cd", bordeaux, "2020-01-02T03:04:05Z"},
+		}, ""},
+		{"short drive", []Scan{
+			{"02ab", bordeaux, "2020-01-02T03:00:00Z"},
+			{"03cd", pauillac, "2020-01-02T04:00:00Z"},
+		}, ""},
+		{"long flight", []Scan{
+			{"02ab", bordeaux, "2020-01-02T00:00:00Z"},
+			{"03cd", shanghai, "2020-01-03T00:00:00Z"},
+		}, ""},
+		{"impossible travel", []Scan{
+			{"02ab", bordeaux, "2020-01-02T00:00:00Z"},
+			{"03cd", shanghai, "2020-01-02T02:00:00Z"},
+		}, "scanned 8809 km apart"},
+		{"too many regions", []Scan{
+			{"02ab", mustGeoPoint(t, "44.8", "-0.6"), "2020-01-01T00:00:00Z"},
+			{"02ab", mustGeoPoint(t, "51.5", "-0.1"), "2020-02-01T00:00:00Z"},
+			{"02ab", mustGeoPoint(t, "40.7", "-74"), "2020-03-01T00:00:00Z"},
+			{"02ab", mustGeoPoint(t, "35.7", "139.7"), "2020-04-01T00:00:00Z"},
+			{"02ab", mustGeoPoint(t, "-33.9", "151.2"), "2020-05-01T00:00:00Z"},
+			{"02ab", mustGeoPoint(t, "-23.5", "-46.6"), "2020-06-01T00:00:00Z"},
+		}, "scanned in more than 5 regions"},
+	}
+	for _, c := range cases {
+		record := LabelRecord{WineLabelID: "125"}
+		reason := ""
+		for i, scan := range c.scans {
+			flagged, err := record.RecordScan(scan)
+			if err != nil {
+				t.Fatalf("%s: scan %d: %v", c.name, i, err)
+			}
+			if flagged != "" && reason != "" {
+				t.Errorf("%s: scan %d flagged the label again", c.name, i)
+			}
+			if flagged != "" {
+				reason = flagged
+			}
+		}
+		if (reason == "") != (c.suspicious == "") || !strings.HasPrefix(reason, c.suspicious) {
+			t.Errorf("%s: flagged %q, want %q", c.name, reason, c.suspicious)
+		}
+		if record.Suspicious != (c.suspicious != "") || record.SuspiciousReason != reason {
+			t.Errorf("%s: record suspicious %v %q", c.name, record.Suspicious, record.SuspiciousReason)
+		}
+		if record.ScanCount != uint64(len(c.scans)) || record.LastScan != c.scans[len(c.scans)-1] {
+			t.Errorf("%s: record has %d scans, last %+v", c.name, record.ScanCount, record.LastScan)
+		}
+		if len(record.ScanRegions) > MAX_SCAN_REGIONS+1 {
+			t.Errorf("%s: record keeps %d regions", c.name, len(record.ScanRegions))
+		}
+	}
+}
+
+func TestRecordScanStaysSuspicious(t *testing.T) {
+	record := LabelRecord{WineLabelID: "125", Suspicious: true, SuspiciousReason: "cloned"}
+	reason, err := record.RecordScan(Scan{"02ab", GeoPoint{}, "2020-01-02T03:04:05Z"})
+	if err != nil || reason != "" || !record.Suspicious || record.SuspiciousReason != "cloned" {
+		t.Errorf("RecordScan = %q, %v; record suspicious %v %q",
+			reason, err, record.Suspicious, record.SuspiciousReason)
+	}
+}
+
+func TestRecordScanInvalidTime(t *testing.T) {
+	record := LabelRecord{WineLabelID: "125"}
+	if _, err := record.RecordScan(Scan{"02ab", GeoPoint{}, "2020-01-02T03:00:00Z"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, scannedAt := range []string{
+		"", "yesterday", "2020-01-02 03:04:05",
+		// Before the last scan.
+		"2020-01-02T02:59:59Z",
+	} {
+		if _, err := record.RecordScan(Scan{"03cd", GeoPoint{}, scannedAt}); err == nil {
+			t.Errorf("RecordScan accepted scan time %q", scannedAt)
+		}
+	}
+	if record.ScanCount != 1 {
+		t.Errorf("Rejected scans were counted")
+	}
+}
@@ -0,0 +1,49 @@
+package protocol
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// The wine-label family is administered through the settings transaction
+// family. ADMINS_SETTING holds a comma separated list of the public keys
+// allowed to manage the organisation registry, and REGULATORS_SETTING the
+// keys of regulators, who may recall any lot. Other tenants use the
+// settings named by AdminsSetting and RegulatorsSetting for their family.
+const (
+	ADMINS_SETTING     string = "wine-label.admins"
+	REGULATORS_SETTING string = "wine-label.regulators"
+
+	SETTINGS_NAMESPACE string = "000000"
+	// Setting keys are split on "." into at most SETTING_KEY_PARTS parts,
+	// each hashed to SETTING_PART_LENGTH characters.
+	SETTING_KEY_PARTS   int = 4
+	SETTING_PART_LENGTH int = 16
+)
+
+// SettingAddress returns the address the settings family stores key at.
+func SettingAddress(key string) string {
+	parts := strings.SplitN(key, ".", SETTING_KEY_PARTS)
+	for len(parts) < SETTING_KEY_PARTS {
+		parts = append(parts, "")
+	}
+	address := SETTINGS_NAMESPACE
+	for _, part := range parts {
+		hash := sha256.Sum256([]byte(part))
+		address += hex.EncodeToString(hash[:])[:SETTING_PART_LENGTH]
+	}
+	return address
+}
+
+// ParseKeyList splits a comma separated setting value into public keys.
+func ParseKeyList(value string) []string {
+	var keys []string
+	for _, key := range strings.Split(value, ",") {
+		key = strings.TrimSpace(key)
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,32 @@
+package protocol
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSettingAddress(t *testing.T) {
+	cases := []struct {
+		key     string
+		address string
+	}{
+		// Address published by the settings family for its own key.
+		{"sawtooth.settings.vote.authorized_keys", "000000a87cb5eafdcca6a8cde0fb0dec1400c5ab274474a6aa82c12840f169a04216b7"},
+		{ADMINS_SETTING, "000000885805f7c1a271f1fa956b808c8f8e3be3b0c44298fc1c14e3b0c44298fc1c14"},
+	}
+	for _, c := range cases {
+		if address := SettingAddress(c.key); address != c.address {
+			t.Errorf("SettingAddress(%q) = %s, want %s", c.key, address, c.address)
+		}
+	}
+}
+
+func TestParseKeyList(t *testing.T) {
+	keys := ParseKeyList(" 02ab, 03cd,,")
+	if want := []string{"02ab", "03cd"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("ParseKeyList = %v, want %v", keys, want)
+	}
+	if keys := ParseKeyList(""); len(keys) != 0 {
+		t.Errorf("ParseKeyList(\"\") = %v", keys)
+	}
+}
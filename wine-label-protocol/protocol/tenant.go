@@ -0,0 +1,50 @@
+package protocol
+
+import (
+	"fmt"
+)
+
+// A processor may serve several consortia on one validator network, each
+// as a tenant with its own family name, and so its own namespace,
+// organisation registry and admin and regulator settings. Event types are
+// the same for every tenant; subscribers tell tenants apart by the
+// namespace of ATTRIBUTE_ADDRESS.
+const (
+	// DEFAULT_TENANT is served as the plain FAMILY_NAME.
+	DEFAULT_TENANT string = "default"
+	// MAX_TENANT_LENGTH bounds tenant names, which are part of the family
+	// name of every transaction.
+	MAX_TENANT_LENGTH int = 32
+)
+
+// TenantFamilyName returns the family name a tenant's transactions are sent
+// under: FAMILY_NAME for DEFAULT_TENANT and FAMILY_NAME-<tenant> for the
+// others. Tenant names are lower case letters, digits and dashes, starting
+// with a letter.
+func TenantFamilyName(tenant string) (string, error) {
+	if tenant == DEFAULT_TENANT {
+		return FAMILY_NAME, nil
+	}
+	if tenant == "" || len(tenant) > MAX_TENANT_LENGTH {
+		return "", fmt.Errorf("Tenant name must be 1 to %d characters", MAX_TENANT_LENGTH)
+	}
+	for i, r := range tenant {
+		letter := r >= 'a' && r <= 'z'
+		if !letter && (i == 0 || (r < '0' || r > '9') && r != '-') {
+			return "", fmt.Errorf("Invalid tenant name %q: use lower case letters, digits and dashes, starting with a letter", tenant)
+		}
+	}
+	return FAMILY_NAME + "-" + tenant, nil
+}
+
+// AdminsSetting returns the setting listing the admins of a family;
+// ADMINS_SETTING for FAMILY_NAME.
+func AdminsSetting(familyName string) string {
+	return familyName + ".admins"
+}
+
+// RegulatorsSetting returns the setting listing the regulators of a
+// family; REGULATORS_SETTING for FAMILY_NAME.
+func RegulatorsSetting(familyName string) string {
+	return familyName + ".regulators"
+}
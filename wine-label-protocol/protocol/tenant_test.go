@@ -0,0 +1,37 @@
+package protocol
+
+import (
+	"testing"
+)
+
+func TestTenantFamilyName(t *testing.T) {
+	cases := []struct {
+		tenant string
+		family string
+		valid  bool
+	}{
+		{DEFAULT_TENANT, FAMILY_NAME, true},
+		{"bordeaux", "wine-label-bordeaux", true},
+		{"napa-2", "wine-label-napa-2", true},
+		{"", "", false},
+		{"Napa", "", false},
+		{"2napa", "", false},
+		{"napa.valley", "", false},
+		{"a-very-long-consortium-name-indeed", "", false},
+	}
+	for _, c := range cases {
+		family, err := TenantFamilyName(c.tenant)
+		if (err == nil) != c.valid || family != c.family {
+			t.Errorf("TenantFamilyName(%q) = %q, %v, want %q", c.tenant, family, err, c.family)
+		}
+	}
+}
+
+func TestTenantSettings(t *testing.T) {
+	if AdminsSetting(FAMILY_NAME) != ADMINS_SETTING || RegulatorsSetting(FAMILY_NAME) != REGULATORS_SETTING {
+		t.Error("Settings of the default family differ from ADMINS_SETTING and REGULATORS_SETTING")
+	}
+	if SettingAddress(AdminsSetting("wine-label-bordeaux")) == SettingAddress(ADMINS_SETTING) {
+		t.Error("Tenants share the admins setting")
+	}
+}
@@ -0,0 +1,105 @@
+package protocol
+
+// TransactionAddresses lists the state a transaction of the family signed
+// by signer reads and writes, which its header must declare as inputs and
+// outputs. Registry transactions read the admins setting, and label
+// transactions read the registry to check that the signer may create
+// labels.
+func TransactionAddresses(familyName string, signer string, payload WineLabelPayload) ([]string, []string) {
+	ns := NewNamespace(familyName)
+	switch payload.Verb {
+	case VERB_REGISTER_ORG, VERB_UPDATE_ORG:
+		outputs := []string{
+			ns.OrganisationAddress(payload.Organisation.OrgID),
+			ns.SpacePrefix(KEY_SPACE),
+		}
+		inputs := []string{
+			ns.OrganisationAddress(payload.Organisation.OrgID),
+			ns.SpacePrefix(KEY_SPACE),
+			SettingAddress(AdminsSetting(familyName)),
+		}
+		return inputs, outputs
+	case VERB_MINT:
+		// Minted labels are new, so only their first history event is
+		// written.
+		ids, _ := payload.Mint.Labels()
+		outputs := make([]string, 0, 2*len(ids))
+		for _, id := range ids {
+			outputs = append(outputs,
+				ns.LabelAddress(id),
+				ns.HistoryAddress(id, 0))
+			// A roll whose records cannot be built is rejected before
+			// anything is written.
+			if record, err := NewMintedRecord(payload.Mint, id, signer); err == nil {
+				outputs = append(outputs, ns.IndexAddresses(record)...)
+			}
+		}
+		if payload.Mint.Lot != "" {
+			outputs = append(outputs, ns.LotAddress(payload.Mint.Lot))
+		}
+		inputs := append([]string{
+			ns.KeyAddress(signer),
+			ns.SpacePrefix(ORG_SPACE),
+		}, outputs...)
+		for _, id := range ids {
+			inputs = append(inputs, ns.LegacyLabelAddress(id))
+		}
+		return inputs, outputs
+	case VERB_CREATE_LOT:
+		outputs := []string{ns.LotAddress(payload.LotDetails.LotID)}
+		inputs := []string{
+			ns.LotAddress(payload.LotDetails.LotID),
+			ns.KeyAddress(signer),
+			ns.SpacePrefix(ORG_SPACE),
+		}
+		return inputs, outputs
+	case VERB_RECALL:
+		outputs := []string{ns.LotAddress(payload.LotDetails.LotID)}
+		inputs := []string{
+			ns.LotAddress(payload.LotDetails.LotID),
+			ns.KeyAddress(signer),
+			ns.SpacePrefix(ORG_SPACE),
+			SettingAddress(RegulatorsSetting(familyName)),
+		}
+		return inputs, outputs
+	}
+	// Labels are read from, and moved away from, the address the original
+	// processor stored them at.
+	outputs := []string{
+		ns.LabelAddress(payload.WineLabelID),
+		ns.LegacyLabelAddress(payload.WineLabelID),
+		ns.HistoryPrefix(payload.WineLabelID),
+	}
+	inputs := []string{
+		ns.LabelAddress(payload.WineLabelID),
+		ns.LegacyLabelAddress(payload.WineLabelID),
+		ns.HistoryPrefix(payload.WineLabelID),
+		ns.KeyAddress(signer),
+		ns.SpacePrefix(ORG_SPACE),
+		// Admins act for the owner of a label upgraded from 1.0.
+		SettingAddress(AdminsSetting(familyName)),
+	}
+	// Verbs that change what a label is indexed under may remove index
+	// entries and leave lots the client does not know about, so they name
+	// the whole space. A label still at its legacy address is indexed by
+	// position the first time it is written, whatever the verb.
+	spaces := []string{GEO_INDEX_SPACE}
+	switch payload.Verb {
+	case VERB_SET:
+		spaces = []string{LOT_SPACE, LOT_INDEX_SPACE, FACILITY_INDEX_SPACE, GEO_INDEX_SPACE}
+		outputs = append(outputs, ns.IndexAddress(
+			OWNER_INDEX_SPACE, signer, payload.WineLabelID))
+	case VERB_DELETE:
+		spaces = append([]string{LOT_SPACE}, INDEX_SPACES...)
+	case VERB_ACCEPT:
+		spaces = []string{OWNER_INDEX_SPACE, GEO_INDEX_SPACE}
+	case VERB_SCAN:
+		// A scan reads the label's lot to report a recall.
+		inputs = append(inputs, ns.SpacePrefix(LOT_SPACE))
+	}
+	for _, space := range spaces {
+		outputs = append(outputs, ns.SpacePrefix(space))
+		inputs = append(inputs, ns.SpacePrefix(space))
+	}
+	return inputs, outputs
+}
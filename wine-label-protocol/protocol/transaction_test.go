@@ -0,0 +1,21 @@
+package protocol
+
+import "testing"
+
+func TestTransactionAddressesMint(t *testing.T) {
+	mint := MintPayload{
+		LabelIDs:  []string{"W-0001"},
+		Print:     PrintEvent{Time: "2020-01-02T03:04:05Z", Printer: "press-1", Facility: "cellar", Operator: "alice"},
+		Longitude: "-0.57918",
+		Lattitude: "44.837789",
+	}
+	_, outputs := TransactionAddresses(FAMILY_NAME, "02ab", WineLabelPayload{Verb: VERB_MINT, Mint: mint})
+	ns := NewNamespace(FAMILY_NAME)
+	want := ns.IndexAddress(FACILITY_INDEX_SPACE, "cellar", "W-0001")
+	for _, address := range outputs {
+		if address == want {
+			return
+		}
+	}
+	t.Errorf("Mint outputs %v miss the facility index entry %v", outputs, want)
+}
@@ -0,0 +1,249 @@
+// Package config reads the processor's configuration from a YAML file and
+// WINE_LABEL_ environment variables. Command line flags are merged in by
+// main, so that each setting is taken from the first of the flags, the
+// environment, the file and the defaults that gives it.
+package config
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	intkey "wine-label/handler"
+
+	"wine-label-protocol/protocol"
+)
+
+const (
+	ENV_PREFIX      string = "WINE_LABEL_"
+	DEFAULT_CONNECT string = "tcp://localhost:4004"
+	DEFAULT_QUEUE   uint   = 100
+)
+
+var (
+	LOG_LEVELS  = []string{"debug", "info", "warn", "error"}
+	LOG_FORMATS = []string{"logfmt", "json"}
+)
+
+// Config is the processor's configuration. Its YAML keys are those of the
+// flags, with underscores for dashes.
+type Config struct {
+	Connect       string   `yaml:"connect"`
+	MaxQueueSize  uint     `yaml:"max_queue_size"`
+	Threads       uint     `yaml:"worker_thread_count"`
+	MetricsListen string   `yaml:"metrics_listen"`
+	LogLevel      string   `yaml:"log_level"`
+	LogFormat     string   `yaml:"log_format"`
+	Policy        Policy   `yaml:"policy"`
+	Tenants       []Tenant `yaml:"tenants"`
+}
+
+// Policy is the validation policy of a tenant. A tenant's policy inherits
+// every field it leaves unset from the top-level policy. The policy is part
+// of the tenant's consensus rules, so every node must be configured with an
+// identical one; each handler's policy_hash is logged to check that.
+type Policy struct {
+	MaxPayloadSize int `yaml:"max_payload_size"`
+	// LabelIDPattern is a regular expression that must match the whole ID
+	// of every new label.
+	LabelIDPattern string `yaml:"label_id_pattern"`
+	// Bounds is the region new labels must be printed in, written as
+	// "south,west,north,east" in degrees.
+	Bounds string `yaml:"bounds"`
+}
+
+// Tenant is a consortium the processor serves, with the policy fields it
+// overrides given alongside its name.
+type Tenant struct {
+	Name   string `yaml:"name"`
+	Policy `yaml:",inline"`
+}
+
+// Default returns the configuration of a processor given no file,
+// environment or flags.
+func Default() *Config {
+	return &Config{
+		Connect:      DEFAULT_CONNECT,
+		MaxQueueSize: DEFAULT_QUEUE,
+		LogFormat:    "logfmt",
+		Policy: Policy{
+			MaxPayloadSize: protocol.MAX_PAYLOAD_SIZE,
+		},
+		Tenants: []Tenant{{Name: protocol.DEFAULT_TENANT}},
+	}
+}
+
+// Load reads the file at path over the configuration. Unknown keys are
+// rejected, so that a misspelt setting is not silently ignored.
+func (self *Config) Load(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(self); err != nil && err != io.EOF {
+		return fmt.Errorf("Cannot read config file %v: %v", path, err)
+	}
+	return nil
+}
+
+// LoadEnv reads the WINE_LABEL_ variables that lookup finds over the
+// configuration. WINE_LABEL_TENANTS is a comma-separated list of tenant
+// names, and replaces the tenants of the file.
+func (self *Config) LoadEnv(lookup func(string) (string, bool)) error {
+	strs := map[string]*string{
+		"CONNECT":          &self.Connect,
+		"METRICS_LISTEN":   &self.MetricsListen,
+		"LOG_LEVEL":        &self.LogLevel,
+		"LOG_FORMAT":       &self.LogFormat,
+		"LABEL_ID_PATTERN": &self.Policy.LabelIDPattern,
+		"BOUNDS":           &self.Policy.Bounds,
+	}
+	for name, field := range strs {
+		if value, ok := lookup(ENV_PREFIX + name); ok {
+			*field = value
+		}
+	}
+	uints := map[string]*uint{
+		"MAX_QUEUE_SIZE":      &self.MaxQueueSize,
+		"WORKER_THREAD_COUNT": &self.Threads,
+	}
+	for name, field := range uints {
+		if value, ok := lookup(ENV_PREFIX + name); ok {
+			parsed, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				return fmt.Errorf("Invalid %v%v: %v", ENV_PREFIX, name, err)
+			}
+			*field = uint(parsed)
+		}
+	}
+	if value, ok := lookup(ENV_PREFIX + "MAX_PAYLOAD_SIZE"); ok {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("Invalid %vMAX_PAYLOAD_SIZE: %v", ENV_PREFIX, err)
+		}
+		self.Policy.MaxPayloadSize = parsed
+	}
+	if value, ok := lookup(ENV_PREFIX + "TENANTS"); ok {
+		self.SetTenants(strings.Split(value, ","))
+	}
+	return nil
+}
+
+// SetTenants replaces the tenants with those named, each inheriting the
+// top-level policy.
+func (self *Config) SetTenants(names []string) {
+	self.Tenants = make([]Tenant, 0, len(names))
+	for _, name := range names {
+		self.Tenants = append(self.Tenants, Tenant{Name: strings.TrimSpace(name)})
+	}
+}
+
+// Validate checks the processor's own settings. The tenants and their
+// policies are checked by Handlers, which the processor calls before it
+// connects.
+func (self *Config) Validate() error {
+	if !strings.Contains(self.Connect, "://") {
+		return fmt.Errorf("Validator endpoint %q must be given as PROTOCOL://HOST:PORT", self.Connect)
+	}
+	if self.MaxQueueSize == 0 {
+		return fmt.Errorf("Max queue size must be at least 1")
+	}
+	if self.MetricsListen != "" {
+		if _, _, err := net.SplitHostPort(self.MetricsListen); err != nil {
+			return fmt.Errorf("Invalid metrics address %q: %v", self.MetricsListen, err)
+		}
+	}
+	if self.LogLevel != "" && !contains(LOG_LEVELS, self.LogLevel) {
+		return fmt.Errorf("Log level %q must be one of %v", self.LogLevel, LOG_LEVELS)
+	}
+	if !contains(LOG_FORMATS, self.LogFormat) {
+		return fmt.Errorf("Log format %q must be one of %v", self.LogFormat, LOG_FORMATS)
+	}
+	return nil
+}
+
+// Handlers builds a handler for each tenant, enforcing its policy. It
+// fails if a tenant is given twice or a policy is invalid. A policy field
+// left unset, or set to null, takes the default.
+func (self *Config) Handlers() ([]*intkey.WineLabelHandler, error) {
+	if len(self.Tenants) == 0 {
+		return nil, fmt.Errorf("No tenants to serve")
+	}
+	handlers := make([]*intkey.WineLabelHandler, 0, len(self.Tenants))
+	served := make(map[string]bool)
+	for _, tenant := range self.Tenants {
+		familyName, err := protocol.TenantFamilyName(tenant.Name)
+		if err != nil {
+			return nil, err
+		}
+		if served[familyName] {
+			return nil, fmt.Errorf("Tenant %v is given twice", tenant.Name)
+		}
+		served[familyName] = true
+		policy, err := self.Policy.inherit(tenant.Policy).handlerPolicy(familyName)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid policy for tenant %v: %v", tenant.Name, err)
+		}
+		handlers = append(handlers, intkey.NewWineLabelHandler(familyName, policy))
+	}
+	return handlers, nil
+}
+
+// inherit returns override with the fields it leaves unset taken from
+// the policy.
+func (self Policy) inherit(override Policy) Policy {
+	if override.MaxPayloadSize != 0 {
+		self.MaxPayloadSize = override.MaxPayloadSize
+	}
+	if override.LabelIDPattern != "" {
+		self.LabelIDPattern = override.LabelIDPattern
+	}
+	if override.Bounds != "" {
+		self.Bounds = override.Bounds
+	}
+	return self
+}
+
+func (self Policy) handlerPolicy(familyName string) (intkey.Policy, error) {
+	policy := intkey.DefaultPolicy(familyName)
+	if self.MaxPayloadSize != 0 {
+		if self.MaxPayloadSize < 0 || self.MaxPayloadSize > protocol.MAX_PAYLOAD_SIZE {
+			return policy, fmt.Errorf("Max payload size %d must be between 1 and %d",
+				self.MaxPayloadSize, protocol.MAX_PAYLOAD_SIZE)
+		}
+		policy.MaxPayloadSize = self.MaxPayloadSize
+	}
+	if self.LabelIDPattern != "" {
+		pattern, err := regexp.Compile("^(?:" + self.LabelIDPattern + ")$")
+		if err != nil {
+			return policy, fmt.Errorf("Invalid label ID pattern: %v", err)
+		}
+		policy.LabelIDPattern = pattern
+	}
+	if self.Bounds != "" {
+		bounds, err := protocol.ParseBoundingBox(self.Bounds)
+		if err != nil {
+			return policy, err
+		}
+		policy.Bounds = &bounds
+	}
+	return policy, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,192 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	intkey "wine-label/handler"
+
+	"wine-label-protocol/protocol"
+)
+
+const testConfig = `
+connect: tcp://validator:4004
+max_queue_size: 50
+worker_thread_count: 4
+metrics_listen: ":9100"
+log_format: json
+policy:
+  max_payload_size: 32768
+  label_id_pattern: 'W-\d{4}'
+tenants:
+  - name: default
+  - name: bordeaux
+    max_payload_size: 4096
+    bounds: "42,-5,51,8"
+`
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "wine-label-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, "processor.yaml")
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func lookup(env map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+}
+
+func TestLoad(t *testing.T) {
+	cfg := Default()
+	if err := cfg.Load(writeConfig(t, testConfig)); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Connect != "tcp://validator:4004" || cfg.MaxQueueSize != 50 || cfg.Threads != 4 ||
+		cfg.MetricsListen != ":9100" || cfg.LogFormat != "json" {
+		t.Errorf("Load read %+v", cfg)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	handlers, err := cfg.Handlers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(handlers) != 2 || handlers[0].FamilyName() != protocol.FAMILY_NAME || handlers[1].FamilyName() != "wine-label-bordeaux" {
+		t.Fatalf("Handlers serve %v", handlers)
+	}
+
+	base := handlers[0].Policy()
+	if base.MaxPayloadSize != 32768 || !hasPattern(base, `W-\d{4}`) || base.Bounds != nil {
+		t.Errorf("Default tenant policy = %+v", base)
+	}
+	bordeaux := handlers[1].Policy()
+	if bordeaux.MaxPayloadSize != 4096 || !hasPattern(bordeaux, `W-\d{4}`) || !hasBounds(t, bordeaux, "42,-5,51,8") {
+		t.Errorf("Bordeaux tenant policy = %+v", bordeaux)
+	}
+}
+
+// hasPattern reports whether a handler policy requires label IDs to match
+// pattern as a whole.
+func hasPattern(policy intkey.Policy, pattern string) bool {
+	return policy.LabelIDPattern != nil && policy.LabelIDPattern.String() == "^(?:"+pattern+")$"
+}
+
+func hasBounds(t *testing.T, policy intkey.Policy, bounds string) bool {
+	t.Helper()
+	want, err := protocol.ParseBoundingBox(bounds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return policy.Bounds != nil && *policy.Bounds == want
+}
+
+func TestLoadInvalid(t *testing.T) {
+	for name, content := range map[string]string{
+		"unknown key": "conect: tcp://validator:4004\n",
+		"bad size":    "policy:\n  max_payload_size: big\n",
+		"not a list":  "tenants: bordeaux\n",
+	} {
+		if err := Default().Load(writeConfig(t, content)); err == nil {
+			t.Errorf("%s: Load accepted the file", name)
+		}
+	}
+	if err := Default().Load(writeConfig(t, "")); err != nil {
+		t.Errorf("Load rejected an empty file: %v", err)
+	}
+	if err := Default().Load(filepath.Join(os.TempDir(), "no-such-wine-label.yaml")); err == nil {
+		t.Error("Load accepted a missing file")
+	}
+}
+
+func TestLoadEnv(t *testing.T) {
+	cfg := Default()
+	if err := cfg.Load(writeConfig(t, testConfig)); err != nil {
+		t.Fatal(err)
+	}
+	err := cfg.LoadEnv(lookup(map[string]string{
+		"WINE_LABEL_CONNECT":          "tcp://other:4004",
+		"WINE_LABEL_MAX_QUEUE_SIZE":   "20",
+		"WINE_LABEL_MAX_PAYLOAD_SIZE": "1024",
+		"WINE_LABEL_BOUNDS":           "0,0,10,10",
+		"WINE_LABEL_TENANTS":          "napa, sonoma",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Connect != "tcp://other:4004" || cfg.MaxQueueSize != 20 || cfg.Threads != 4 {
+		t.Errorf("LoadEnv read %+v", cfg)
+	}
+	if len(cfg.Tenants) != 2 || cfg.Tenants[0].Name != "napa" || cfg.Tenants[1].Name != "sonoma" {
+		t.Fatalf("LoadEnv read tenants %+v", cfg.Tenants)
+	}
+	handlers, err := cfg.Handlers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sonoma := handlers[1].Policy()
+	if handlers[1].FamilyName() != "wine-label-sonoma" || sonoma.MaxPayloadSize != 1024 ||
+		!hasBounds(t, sonoma, "0,0,10,10") || !hasPattern(sonoma, `W-\d{4}`) {
+		t.Errorf("Sonoma tenant policy = %+v", sonoma)
+	}
+
+	for _, env := range []map[string]string{
+		{"WINE_LABEL_MAX_QUEUE_SIZE": "many"},
+		{"WINE_LABEL_WORKER_THREAD_COUNT": "-1"},
+		{"WINE_LABEL_MAX_PAYLOAD_SIZE": "1k"},
+	} {
+		if err := Default().LoadEnv(lookup(env)); err == nil {
+			t.Errorf("LoadEnv accepted %v", env)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Default().Validate(); err != nil {
+		t.Fatalf("Default configuration is invalid: %v", err)
+	}
+	if _, err := Default().Handlers(); err != nil {
+		t.Fatalf("Default tenants are invalid: %v", err)
+	}
+	for name, change := range map[string]func(cfg *Config){
+		"no endpoint scheme":  func(cfg *Config) { cfg.Connect = "localhost:4004" },
+		"empty queue":         func(cfg *Config) { cfg.MaxQueueSize = 0 },
+		"bad metrics address": func(cfg *Config) { cfg.MetricsListen = "9100" },
+		"bad log level":       func(cfg *Config) { cfg.LogLevel = "loud" },
+		"bad log format":      func(cfg *Config) { cfg.LogFormat = "xml" },
+		"no tenants":          func(cfg *Config) { cfg.Tenants = nil },
+		"bad tenant":          func(cfg *Config) { cfg.Tenants = []Tenant{{Name: "Bordeaux"}} },
+		"duplicate tenant":    func(cfg *Config) { cfg.Tenants = []Tenant{{Name: "napa"}, {Name: "napa"}} },
+		"payload too large":   func(cfg *Config) { cfg.Policy.MaxPayloadSize = protocol.MAX_PAYLOAD_SIZE + 1 },
+		"bad pattern":         func(cfg *Config) { cfg.Policy.LabelIDPattern = "W-(" },
+		"bad bounds":          func(cfg *Config) { cfg.Policy.Bounds = "51,-5,42,8" },
+		"bad tenant bounds": func(cfg *Config) {
+			cfg.Tenants = []Tenant{{Name: "napa", Policy: Policy{Bounds: "north"}}}
+		},
+	} {
+		cfg := Default()
+		change(cfg)
+		err := cfg.Validate()
+		if err == nil {
+			_, err = cfg.Handlers()
+		}
+		if err == nil {
+			t.Errorf("%s: configuration accepted", name)
+		} else if name == "bad tenant bounds" && !strings.Contains(err.Error(), "napa") {
+			t.Errorf("%s: error %q does not name the tenant", name, err)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"wine-label-protocol/protocol"
+)
+
+// applyClaim lets whoever holds the secret printed under a label's
+// scratch-off claim the label, once. A second claim, or a wrong secret,
+// suggests a copied label. Rejecting it would leave no trace, since a
+// rejected transaction leaves no state or events behind, so it is applied
+// instead: it adds to the label's history and is announced by
+// EVENT_CLAIM_REJECTED.
+func (self *WineLabelHandler) applyClaim(tx *labelTransaction) error {
+	if err := self.requireLabel(tx); err != nil {
+		return err
+	}
+	if !tx.label.Secret.IsSet() {
+		return reject(REASON_NOT_FOUND,
+			fmt.Sprintf("Cannot claim wine label %v: label has no secret", tx.payload.WineLabelID))
+	}
+	record := *tx.label
+	log := tx.log
+	reason := ""
+	switch {
+	case tx.label.Claim != nil:
+		reason = fmt.Sprintf("already claimed at %v", tx.label.Claim.ClaimedAt)
+		log = log.WithFields(logrus.Fields{
+			"first_claimant":   tx.label.Claim.Claimant,
+			"first_claimed_at": tx.label.Claim.ClaimedAt,
+		})
+	case !tx.label.Secret.Matches(tx.payload.RevealedSecret):
+		reason = "secret does not match"
+	default:
+		record.Claim = &protocol.Claim{Claimant: tx.signer, ClaimedAt: tx.payload.ClaimedAt}
+	}
+	if err := self.commit(tx, &record); err != nil {
+		return err
+	}
+	if reason == "" {
+		return nil
+	}
+	log.WithField("reason", reason).Warn("Counterfeit signal: wine label claim rejected")
+	return self.emitAlert(tx, protocol.EVENT_CLAIM_REJECTED, record, reason)
+}
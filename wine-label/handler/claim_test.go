@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"testing"
+
+	"wine-label-protocol/protocol"
+)
+
+func TestApplyClaim(t *testing.T) {
+	created := []step{{wineryKey, setLabelWithSecret("125"), ""}}
+	claimed := append(created, step{consumerKey, claim("125", testSecret), ""})
+	runCases(t, []handlerCase{
+		{name: "claim", setup: created, step: step{consumerKey, claim("125", testSecret), ""},
+			check: func(t *testing.T, state *MemoryState) {
+				record := getLabel(t, state, "125")
+				if record.Claim == nil || record.Claim.Claimant != consumerKey || record.Owner != wineryKey {
+					t.Errorf("claim: record = %+v", record)
+				}
+				wantEvents(t, "claim", state, protocol.EVENT_CLAIMED)
+			}},
+		{name: "wrong secret", setup: created, step: step{consumerKey, claim("125", "guess"), ""},
+			check: func(t *testing.T, state *MemoryState) {
+				record := getLabel(t, state, "125")
+				if record.Claim != nil || record.HistoryLength != 2 {
+					t.Errorf("wrong secret: record = %+v", record)
+				}
+				wantEvents(t, "wrong secret", state, protocol.EVENT_UPDATED, protocol.EVENT_CLAIM_REJECTED)
+				wantAlert(t, "wrong secret", state, protocol.EVENT_CLAIM_REJECTED, "")
+			}},
+		{name: "claim twice", setup: claimed, step: step{otherKey, claim("125", testSecret), ""},
+			check: func(t *testing.T, state *MemoryState) {
+				record := getLabel(t, state, "125")
+				if record.Claim == nil || record.Claim.Claimant != consumerKey || record.HistoryLength != 3 {
+					t.Errorf("claim twice: record = %+v", record)
+				}
+				wantEvents(t, "claim twice", state, protocol.EVENT_UPDATED, protocol.EVENT_CLAIM_REJECTED)
+				wantAlert(t, "claim twice", state, protocol.EVENT_CLAIM_REJECTED, "")
+			}},
+		{name: "no secret", setup: []step{{wineryKey, setLabel("125"), ""}}, step: step{consumerKey, claim("125", testSecret), ""}, invalid: true},
+		{name: "no such label", step: step{consumerKey, claim("125", testSecret), ""}, invalid: true},
+	})
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/sawtooth-sdk-go/processor"
+
+	"wine-label-protocol/protocol"
+)
+
+// labelEventType picks the event announcing what tx did to a label; record
+// is the label's new state, nil if it was withdrawn.
+func labelEventType(tx *labelTransaction, record *protocol.LabelRecord) string {
+	switch {
+	case record == nil:
+		return protocol.EVENT_DELETED
+	case tx.label == nil:
+		return protocol.EVENT_CREATED
+	case tx.payload.Verb == protocol.VERB_ACCEPT:
+		return protocol.EVENT_TRANSFERRED
+	case tx.payload.Verb == protocol.VERB_SCAN:
+		return protocol.EVENT_SCANNED
+	case tx.payload.Verb == protocol.VERB_CLAIM && tx.label.Claim == nil && record.Claim != nil:
+		return protocol.EVENT_CLAIMED
+	}
+	return protocol.EVENT_UPDATED
+}
+
+// emit publishes a label event for subscribers and attaches the resulting
+// record to the transaction receipt.
+func (self *WineLabelHandler) emit(tx *labelTransaction, eventType string, data []byte) error {
+	attributes := []processor.Attribute{
+		{Key: protocol.ATTRIBUTE_LABEL_ID, Value: tx.payload.WineLabelID},
+		{Key: protocol.ATTRIBUTE_SIGNER, Value: tx.signer},
+		{Key: protocol.ATTRIBUTE_ADDRESS, Value: tx.address},
+	}
+	err := tx.context.AddEvent(eventType, attributes, data)
+	if err != nil {
+		return err
+	}
+	return tx.context.AddReceiptData(data)
+}
+
+// emitAlert publishes an event about a label that needs attention, giving
+// the reason. The record was already attached to the receipt by the
+// transaction's own event.
+func (self *WineLabelHandler) emitAlert(tx *labelTransaction, eventType string, record protocol.LabelRecord, reason string) error {
+	data, err := protocol.EncodeRecord(record)
+	if err != nil {
+		return &processor.InternalError{Msg: fmt.Sprint("Failed to encode state: ", err)}
+	}
+	attributes := []processor.Attribute{
+		{Key: protocol.ATTRIBUTE_LABEL_ID, Value: tx.payload.WineLabelID},
+		{Key: protocol.ATTRIBUTE_SIGNER, Value: tx.signer},
+		{Key: protocol.ATTRIBUTE_ADDRESS, Value: tx.address},
+		{Key: protocol.ATTRIBUTE_REASON, Value: reason},
+	}
+	return tx.context.AddEvent(eventType, attributes, data)
+}
@@ -18,142 +18,603 @@
 package handler
 
 import (
-	"crypto/sha512"
-	"encoding/hex"
 	"fmt"
-	"strings"
+	"regexp"
+	"time"
 
-	cbor "github.com/brianolson/cbor_go"
-	"github.com/hyperledger/sawtooth-sdk-go/logging"
 	"github.com/hyperledger/sawtooth-sdk-go/processor"
 	"github.com/hyperledger/sawtooth-sdk-go/protobuf/processor_pb2"
+	"github.com/sirupsen/logrus"
+
+	"wine-label-protocol/protocol"
 )
 
-var logger *logging.Logger = logging.Get()
+// logger is where the handler logs; every entry logged while applying a
+// transaction carries its signature, signer, verb and IDs.
+var logger *logrus.Logger = logrus.StandardLogger()
 
-type WineLabelPayload struct {
-	Payload
-	Verb string
+type WineLabelHandler struct {
+	familyName string
+	namespace  protocol.Namespace
+	policy     Policy
+	metrics    *Metrics
 }
 
-type Payload struct {
-	WineLabelID string
-	PrintedAt   string
-	Longitude   string
-	Lattitude   string
+// Policy is the configuration a handler enforces for its tenant. It decides
+// which transactions are valid, so every processor serving the tenant must
+// enforce an identical policy or validators will disagree on blocks;
+// compare their Hash.
+type Policy struct {
+	// MaxPayloadSize bounds the size of a transaction's payload, below the
+	// protocol's own MAX_PAYLOAD_SIZE if the tenant wants.
+	MaxPayloadSize int
+	// LabelIDPattern, if set, must match the ID of every new label; anchor
+	// it to match whole IDs.
+	LabelIDPattern *regexp.Regexp
+	// Bounds, if set, is the region every new label must be printed in.
+	Bounds *protocol.BoundingBox
+	// AdminsSetting and RegulatorsSetting name the on-chain settings that
+	// list the tenant's admins and regulators.
+	AdminsSetting     string
+	RegulatorsSetting string
 }
 
-type WineLabelHandler struct {
-	namespace string
+// DefaultPolicy returns the policy of a family unless the processor is
+// configured otherwise.
+func DefaultPolicy(familyName string) Policy {
+	return Policy{
+		MaxPayloadSize:    protocol.MAX_PAYLOAD_SIZE,
+		AdminsSetting:     protocol.AdminsSetting(familyName),
+		RegulatorsSetting: protocol.RegulatorsSetting(familyName),
+	}
 }
 
-func NewWineLabelHandler(namespace string) *WineLabelHandler {
+// Hash returns a short digest of the policy, which the processor logs so
+// that operators can check every node enforces the same one.
+func (self Policy) Hash() string {
+	pattern, bounds := "", ""
+	if self.LabelIDPattern != nil {
+		pattern = self.LabelIDPattern.String()
+	}
+	if self.Bounds != nil {
+		bounds = fmt.Sprintf("%v %v", self.Bounds.Min, self.Bounds.Max)
+	}
+	return protocol.Hexdigest(fmt.Sprintf("%d %q %q %q %q", self.MaxPayloadSize,
+		pattern, bounds, self.AdminsSetting, self.RegulatorsSetting))[:16]
+}
+
+// NewWineLabelHandler returns a handler for the transactions of one family,
+// which it serves in the family's own namespace.
+func NewWineLabelHandler(familyName string, policy Policy) *WineLabelHandler {
 	return &WineLabelHandler{
-		namespace: namespace,
+		familyName: familyName,
+		namespace:  protocol.NewNamespace(familyName),
+		policy:     policy,
 	}
 }
 
-const (
-	MIN_VALUE       = 0
-	MAX_VALUE       = 4294967295
-	MAX_NAME_LENGTH = 20
-	FAMILY_NAME     = "wine-label"
-)
+// SetMetrics makes the handler record every transaction it applies.
+func (self *WineLabelHandler) SetMetrics(metrics *Metrics) {
+	self.metrics = metrics
+}
+
+// Policy returns the policy the handler enforces.
+func (self *WineLabelHandler) Policy() Policy {
+	return self.policy
+}
 
 func (self *WineLabelHandler) FamilyName() string {
-	return FAMILY_NAME
+	return self.familyName
 }
 
 func (self *WineLabelHandler) FamilyVersions() []string {
-	return []string{"1.0"}
+	return protocol.FAMILY_VERSIONS
 }
 
 func (self *WineLabelHandler) Namespaces() []string {
-	return []string{self.namespace}
+	return []string{string(self.namespace)}
+}
+
+// labelTransaction is what every verb works from: the decoded payload and
+// the family version it was sent under, the key that signed it and the
+// label currently stored at its address, the state writes and deletions
+// staged so far, and the log entry tagged with the transaction.
+type labelTransaction struct {
+	context       State
+	familyVersion string
+	payload       protocol.WineLabelPayload
+	signer        string
+	address       string
+	label         *protocol.LabelRecord
+	// legacy is set when label was read from its LegacyLabelAddress, which
+	// the original processor wrote without any index entries.
+	legacy    bool
+	updates   map[string][]byte
+	deletions []string
+	log       *logrus.Entry
 }
 
 func (self *WineLabelHandler) Apply(request *processor_pb2.TpProcessRequest, context *processor.Context) error {
+	return self.ApplyState(request, context)
+}
+
+// ApplyState applies a transaction to any State, so that the handler can
+// run against a MemoryState as well as a validator's context.
+func (self *WineLabelHandler) ApplyState(request *processor_pb2.TpProcessRequest, context State) error {
+	err := self.apply(request, context)
+	if rejected, ok := err.(*rejection); ok {
+		return rejected.InvalidTransactionError
+	}
+	return err
+}
+
+// apply applies a transaction and records its outcome. An invalid
+// transaction's error is a *rejection, giving the reason.
+func (self *WineLabelHandler) apply(request *processor_pb2.TpProcessRequest, context State) error {
+	started := time.Now()
+	if self.metrics != nil {
+		done := self.metrics.begin(self.familyName)
+		defer done()
+		context = self.metrics.countState(self.familyName, context)
+	}
+	log := logger.WithFields(logrus.Fields{
+		"family":    self.familyName,
+		"signature": request.GetSignature(),
+		"signer":    request.GetHeader().GetSignerPublicKey(),
+	})
+	payload, err := self.decodeRequest(request)
+	if err == nil {
+		log = log.WithFields(payloadFields(payload))
+		log.Debug("Applying transaction")
+		err = self.dispatch(request, payload, context, log)
+	}
+	elapsed := time.Since(started)
+	if self.metrics != nil {
+		self.metrics.observe(self.familyName, payload.Verb, err, elapsed)
+	}
+	logOutcome(log, err, elapsed)
+	return err
+}
+
+func (self *WineLabelHandler) decodeRequest(request *processor_pb2.TpProcessRequest) (protocol.WineLabelPayload, error) {
 	payloadData := request.GetPayload()
 	if payloadData == nil {
-		return &processor.InvalidTransactionError{Msg: "Must contain payload"}
+		return protocol.WineLabelPayload{}, reject(REASON_MALFORMED, "Must contain payload")
 	}
-	var payload WineLabelPayload
-	err := DecodeCBOR(payloadData, &payload)
+	if limit := self.policy.MaxPayloadSize; limit > 0 && len(payloadData) > limit {
+		return protocol.WineLabelPayload{}, reject(REASON_MALFORMED,
+			fmt.Sprintf("Payload of %d bytes exceeds the limit of %d", len(payloadData), limit))
+	}
+	payload, err := protocol.DecodePayload(request.GetHeader().GetFamilyVersion(), payloadData)
 	if err != nil {
-		return &processor.InvalidTransactionError{
-			Msg: fmt.Sprint("Failed to decode payload: ", err),
-		}
+		return protocol.WineLabelPayload{}, reject(REASON_MALFORMED,
+			fmt.Sprint("Failed to decode payload: ", err))
+	}
+	return payload, nil
+}
+
+// dispatch applies a decoded payload by its verb.
+func (self *WineLabelHandler) dispatch(request *processor_pb2.TpProcessRequest, payload protocol.WineLabelPayload, context State, log *logrus.Entry) error {
+	familyVersion := request.GetHeader().GetFamilyVersion()
+	signer := request.GetHeader().GetSignerPublicKey()
+	switch payload.Verb {
+	case protocol.VERB_REGISTER_ORG, protocol.VERB_UPDATE_ORG:
+		return self.applyOrganisation(context, signer, payload)
+	case protocol.VERB_MINT:
+		return self.applyMint(context, familyVersion, signer, payload, log)
+	case protocol.VERB_CREATE_LOT:
+		return self.applyCreateLot(context, signer, payload)
+	case protocol.VERB_RECALL:
+		return self.applyRecall(context, signer, payload)
+	}
+
+	if len(payload.WineLabelID) == 0 {
+		return reject(REASON_MALFORMED, "Should be valid wine label ID")
 	}
 
+	address := self.namespace.LabelAddress(payload.WineLabelID)
+	label, foundAt, err := self.getLabel(context, payload.Verb, payload.WineLabelID)
 	if err != nil {
-		logger.Error("Bad payload: ", payloadData)
-		return &processor.InternalError{Msg: fmt.Sprint("Failed to decode payload: ", err)}
+		return err
+	}
+	tx := &labelTransaction{
+		context:       context,
+		familyVersion: familyVersion,
+		payload:       payload,
+		signer:        signer,
+		address:       address,
+		label:         label,
+		legacy:        foundAt != "" && foundAt != address,
+		updates:       make(map[string][]byte),
+		log:           log,
+	}
+	if tx.legacy {
+		// The label moves to its current address when it is written.
+		tx.deletions = append(tx.deletions, foundAt)
 	}
 
-	verb := payload.Verb
-	id := payload.WineLabelID
-	state := &Payload{payload.WineLabelID, payload.PrintedAt, payload.Lattitude, payload.Longitude}
+	switch payload.Verb {
+	case protocol.VERB_SET:
+		return self.applySet(tx)
+	case protocol.VERB_DELETE:
+		return self.applyDelete(tx)
+	case protocol.VERB_OFFER:
+		return self.applyOffer(tx)
+	case protocol.VERB_ACCEPT:
+		return self.applyAccept(tx)
+	case protocol.VERB_CANCEL:
+		return self.applyCancel(tx)
+	case protocol.VERB_SCAN:
+		return self.applyScan(tx)
+	case protocol.VERB_CLAIM:
+		return self.applyClaim(tx)
+	}
+	if status, ok := protocol.StatusForVerb(payload.Verb); ok {
+		return self.applyTransition(tx, status)
+	}
+	return reject(REASON_MALFORMED, fmt.Sprintf("Invalid verb: %v", payload.Verb))
+}
 
-	if len(id) == 0 {
-		return &processor.InvalidTransactionError{
-			Msg: fmt.Sprintf(
-				"Should be valid wine label ID",
-				MAX_NAME_LENGTH),
+// applySet creates a label owned by the signer, or lets the owner correct
+// the print details of a label that has not been applied to a bottle yet.
+// Payloads sent under FAMILY_VERSION_2 must carry a print event. Its time
+// is not checked against this validator's clock, which other validators do
+// not share; the client checks it against its own.
+func (self *WineLabelHandler) applySet(tx *labelTransaction) error {
+	event := tx.payload.Print
+	if event.IsSet() || tx.familyVersion == protocol.FAMILY_VERSION_2 {
+		if err := event.Check(); err != nil {
+			return reject(REASON_VALIDATION,
+				fmt.Sprintf("Cannot set wine label %v: %v", tx.payload.WineLabelID, err))
+		}
+	}
+	record, err := protocol.NewLabelRecord(tx.payload.Payload, tx.signer)
+	if err != nil {
+		return reject(REASON_VALIDATION, err.Error())
+	}
+	if err := self.checkPosition(tx.payload.Verb, &record); err != nil {
+		return err
+	}
+	if tx.label != nil {
+		if err := self.requireOwner(tx); err != nil {
+			return err
+		}
+		if tx.label.Status != protocol.STATUS_PRINTED {
+			return reject(REASON_CONFLICT,
+				fmt.Sprintf("Cannot set wine label %v: label is already %v",
+					tx.payload.WineLabelID, tx.label.Status))
 		}
+		record.Status = tx.label.Status
+		record.PendingOwner = tx.label.PendingOwner
+		if !event.IsSet() {
+			record.Printer = tx.label.Printer
+			record.Facility = tx.label.Facility
+			record.PrintTime = tx.label.PrintTime
+			record.Operator = tx.label.Operator
+		}
+		record.ScanCount = tx.label.ScanCount
+		record.LastScan = tx.label.LastScan
+		record.ScanRegions = tx.label.ScanRegions
+		record.Suspicious = tx.label.Suspicious
+		record.SuspiciousReason = tx.label.SuspiciousReason
+		record.Claim = tx.label.Claim
+		if !record.Secret.IsSet() {
+			record.Secret = tx.label.Secret
+		}
+	} else {
+		if err := self.checkLabelID(tx.payload.Verb, tx.payload.WineLabelID); err != nil {
+			return err
+		}
+		if err := self.requireLabelCreator(tx); err != nil {
+			return err
+		}
+		if err := self.requireNoHistory(tx); err != nil {
+			return err
+		}
+	}
+	previousLot := ""
+	if tx.label != nil {
+		previousLot = tx.label.Lot
+	}
+	if err := self.moveLot(tx, previousLot, record.Lot); err != nil {
+		return err
 	}
-	if !(verb == "set" || verb == "del") {
-		return &processor.InvalidTransactionError{Msg: fmt.Sprintf("Invalid verb: %v", verb)}
+	return self.commit(tx, &record)
+}
+
+// checkLabelID rejects the ID of a new label that does not match the
+// tenant's LabelIDPattern.
+func (self *WineLabelHandler) checkLabelID(verb string, id string) error {
+	pattern := self.policy.LabelIDPattern
+	if pattern == nil {
+		return nil
 	}
+	if !pattern.MatchString(id) {
+		return reject(REASON_VALIDATION,
+			fmt.Sprintf("Cannot %v wine label %v: ID does not match %v", verb, id, pattern))
+	}
+	return nil
+}
 
-	hashed_labled_id := Hexdigest(payload.WineLabelID)
-	address := self.namespace + hashed_labled_id[len(hashed_labled_id)-64:]
-	results, err := context.GetState([]string{address})
-	if err != nil {
+// checkPosition rejects a label printed outside the tenant's Bounds.
+func (self *WineLabelHandler) checkPosition(verb string, record *protocol.LabelRecord) error {
+	bounds := self.policy.Bounds
+	if bounds == nil || bounds.Contains(record.Position) {
+		return nil
+	}
+	return reject(REASON_VALIDATION,
+		fmt.Sprintf("Cannot %v wine label %v: position %v is outside %v to %v",
+			verb, record.WineLabelID, record.Position, bounds.Min, bounds.Max))
+}
+
+// applyDelete withdraws a label, freeing its place in its lot.
+func (self *WineLabelHandler) applyDelete(tx *labelTransaction) error {
+	if err := self.requireOwner(tx); err != nil {
+		return err
+	}
+	if err := self.moveLot(tx, tx.label.Lot, ""); err != nil {
 		return err
 	}
+	return self.commit(tx, nil)
+}
 
-	data, exists := results[address]
-	if exists && verb == "del" {
-		data, _ = EncodeCBOR(Payload{})
-	} else {
-		data, _ = EncodeCBOR(state)
+// applyTransition moves a label along its lifecycle, rejecting any move the
+// lifecycle does not allow from the label's current status.
+func (self *WineLabelHandler) applyTransition(tx *labelTransaction, next protocol.LabelStatus) error {
+	if err := self.requireOwner(tx); err != nil {
+		return err
 	}
+	if !protocol.CanTransition(tx.label.Status, next) {
+		return reject(REASON_CONFLICT,
+			fmt.Sprintf("Cannot %v wine label %v: label is %v, allowed next statuses are %v",
+				tx.payload.Verb, tx.payload.WineLabelID, tx.label.Status, protocol.NextStatuses(tx.label.Status)))
+	}
+	record := *tx.label
+	record.Status = next
+	return self.commit(tx, &record)
+}
 
-	addresses, err := context.SetState(map[string][]byte{
-		address: data,
-	})
+// applyOffer opens a custody transfer from the owner to the recipient. The
+// label does not change hands until the recipient accepts.
+func (self *WineLabelHandler) applyOffer(tx *labelTransaction) error {
+	if err := self.requireOwner(tx); err != nil {
+		return err
+	}
+	recipient := tx.payload.Recipient
+	if recipient == "" || recipient == tx.label.Owner {
+		return reject(REASON_VALIDATION,
+			fmt.Sprintf("Cannot offer wine label %v: recipient must be another public key",
+				tx.payload.WineLabelID))
+	}
+	if tx.label.PendingOwner != "" {
+		return reject(REASON_CONFLICT,
+			fmt.Sprintf("Cannot offer wine label %v: already offered to %v",
+				tx.payload.WineLabelID, tx.label.PendingOwner))
+	}
+	record := *tx.label
+	record.PendingOwner = recipient
+	return self.commit(tx, &record)
+}
+
+// applyAccept completes a custody transfer; only the recipient named in the
+// open offer may accept it.
+func (self *WineLabelHandler) applyAccept(tx *labelTransaction) error {
+	if err := self.requireLabel(tx); err != nil {
+		return err
+	}
+	if tx.label.PendingOwner == "" || tx.label.PendingOwner != tx.signer {
+		return reject(REASON_UNAUTHORIZED,
+			fmt.Sprintf("Cannot accept wine label %v: not offered to %v",
+				tx.payload.WineLabelID, tx.signer))
+	}
+	record := *tx.label
+	record.Owner = tx.signer
+	record.PendingOwner = ""
+	return self.commit(tx, &record)
+}
+
+// applyCancel withdraws an open offer; either the owner or the recipient
+// may cancel it.
+func (self *WineLabelHandler) applyCancel(tx *labelTransaction) error {
+	if err := self.requireLabel(tx); err != nil {
+		return err
+	}
+	if tx.label.PendingOwner == "" {
+		return reject(REASON_NOT_FOUND,
+			fmt.Sprintf("Cannot cancel transfer of wine label %v: no open offer",
+				tx.payload.WineLabelID))
+	}
+	if tx.signer != tx.label.Owner && tx.signer != tx.label.PendingOwner {
+		return reject(REASON_UNAUTHORIZED,
+			fmt.Sprintf("Cannot cancel transfer of wine label %v: %v is neither owner nor recipient",
+				tx.payload.WineLabelID, tx.signer))
+	}
+	record := *tx.label
+	record.PendingOwner = ""
+	return self.commit(tx, &record)
+}
+
+func (self *WineLabelHandler) requireLabel(tx *labelTransaction) error {
+	if tx.label == nil {
+		return reject(REASON_NOT_FOUND,
+			fmt.Sprintf("Cannot %v wine label %v: no such label",
+				tx.payload.Verb, tx.payload.WineLabelID))
+	}
+	return nil
+}
+
+// requireNoHistory rejects creating a label whose ID was used before. A
+// deleted label keeps its history, and its ID is not handed out again.
+func (self *WineLabelHandler) requireNoHistory(tx *labelTransaction) error {
+	address := self.namespace.HistoryAddress(tx.payload.WineLabelID, 0)
+	results, err := tx.context.GetState([]string{address})
 	if err != nil {
 		return err
 	}
-	if len(addresses) == 0 {
-		return &processor.InternalError{Msg: "No addresses in set response"}
+	if _, exists := results[address]; exists {
+		return reject(REASON_CONFLICT,
+			fmt.Sprintf("Cannot set wine label %v: label was withdrawn", tx.payload.WineLabelID))
 	}
+	return nil
+}
 
+// requireOwner rejects the transaction unless the label exists and was
+// signed by its owner.
+func (self *WineLabelHandler) requireOwner(tx *labelTransaction) error {
+	if err := self.requireLabel(tx); err != nil {
+		return err
+	}
+	owner := tx.signer == tx.label.Owner
+	if tx.label.Owner == "" {
+		// Labels upgraded from the original processor record no owner;
+		// the admins hold them until they offer them to their owners.
+		admin, err := self.isAdmin(tx.context, tx.signer)
+		if err != nil {
+			return err
+		}
+		owner = admin
+	}
+	if !owner {
+		return reject(REASON_UNAUTHORIZED,
+			fmt.Sprintf("Cannot %v wine label %v: signer %v is not the owner",
+				tx.payload.Verb, tx.payload.WineLabelID, tx.signer))
+	}
 	return nil
 }
 
-func EncodeCBOR(value interface{}) ([]byte, error) {
-	data, err := cbor.Dumps(value)
-	return data, err
+// getLabel reads a label from its address, or else from the address the
+// original processor stored it at, returning nil if there is none along
+// with the address it was found at. The original processor deleted a label
+// by storing an empty one, which counts as none.
+func (self *WineLabelHandler) getLabel(context State, verb string, labelID string) (*protocol.LabelRecord, string, error) {
+	addresses := []string{self.namespace.LabelAddress(labelID), self.namespace.LegacyLabelAddress(labelID)}
+	results, err := context.GetState(addresses)
+	if err != nil {
+		return nil, "", err
+	}
+	for _, address := range addresses {
+		data, exists := results[address]
+		if !exists {
+			continue
+		}
+		record, err := protocol.DecodeLabelRecord(data)
+		if err != nil && address != addresses[0] {
+			// The original processor stored whatever coordinates it was
+			// sent, so some of its labels cannot be upgraded. Retrying
+			// will not change that.
+			return nil, "", reject(REASON_VALIDATION,
+				fmt.Sprintf("Cannot %v wine label %v: %v", verb, labelID, err))
+		}
+		if err != nil {
+			return nil, "", &processor.InternalError{
+				Msg: fmt.Sprintf("Failed to decode label at %v: %v", address, err),
+			}
+		}
+		if record.WineLabelID == "" {
+			continue
+		}
+		return &record, address, nil
+	}
+	return nil, "", nil
 }
 
-func DecodeCBOR(data []byte, pointer interface{}) error {
-	defer func() error {
-		if recover() != nil {
-			return &processor.InvalidTransactionError{Msg: "Failed to decode payload"}
+// getState decodes the record stored at address into pointer, reporting
+// whether there was one.
+func getState(context State, address string, pointer interface{}) (bool, error) {
+	results, err := context.GetState([]string{address})
+	if err != nil {
+		return false, err
+	}
+	data, exists := results[address]
+	if !exists {
+		return false, nil
+	}
+	err = protocol.DecodeRecord(data, pointer)
+	if err != nil {
+		return false, &processor.InternalError{
+			Msg: fmt.Sprintf("Failed to decode state at %v: %v", address, err),
 		}
-		return nil
-	}()
-	err := cbor.Loads(data, pointer)
+	}
+	return true, nil
+}
+
+// commit appends the history event for tx, stores the label's new state
+// and announces the change; a nil record withdraws the label.
+func (self *WineLabelHandler) commit(tx *labelTransaction, record *protocol.LabelRecord) error {
+	data, err := self.stage(tx, record)
 	if err != nil {
 		return err
 	}
-	return nil
+
+	addresses, err := tx.context.SetState(tx.updates)
+	if err != nil {
+		return err
+	}
+	if len(addresses) != len(tx.updates) {
+		return &processor.InternalError{Msg: "Missing addresses in set response"}
+	}
+
+	if record == nil {
+		tx.deletions = append(tx.deletions, tx.address)
+	}
+	if len(tx.deletions) > 0 {
+		addresses, err := tx.context.DeleteState(tx.deletions)
+		if err != nil {
+			return err
+		}
+		// Index entries of labels written before indexing was added
+		// may be missing; only the label itself must have been deleted.
+		if record == nil && len(addresses) == 0 {
+			return &processor.InternalError{Msg: "No addresses in delete response"}
+		}
+	}
+	return self.emit(tx, labelEventType(tx, record), data)
 }
 
-func Hexdigest(str string) string {
-	hash := sha512.New()
-	hash.Write([]byte(str))
-	hashBytes := hash.Sum(nil)
-	return strings.ToLower(hex.EncodeToString(hashBytes))
+// stage adds the history event for tx, the label's new state and its index
+// entries to tx.updates, returning the encoded state to announce. A nil
+// record adds only the history event.
+func (self *WineLabelHandler) stage(tx *labelTransaction, record *protocol.LabelRecord) ([]byte, error) {
+	event := protocol.HistoryEvent{
+		WineLabelID: tx.payload.WineLabelID,
+		Verb:        tx.payload.Verb,
+		Signer:      tx.signer,
+		Location:    tx.payload.PrintedAt,
+		ClaimedAt:   tx.payload.ClaimedAt,
+	}
+	if tx.label != nil {
+		event.Sequence = tx.label.HistoryLength
+		event.PreviousHash = tx.label.HistoryHead
+	}
+	if tx.payload.Lattitude != "" || tx.payload.Longitude != "" {
+		position, err := protocol.ParseGeoPoint(tx.payload.Lattitude, tx.payload.Longitude)
+		if err != nil {
+			return nil, reject(REASON_VALIDATION, err.Error())
+		}
+		event.Position = position
+		event.HasPosition = true
+	}
+
+	eventData, err := protocol.EncodeRecord(event)
+	if err != nil {
+		return nil, &processor.InternalError{Msg: fmt.Sprint("Failed to encode history event: ", err)}
+	}
+	tx.updates[self.namespace.HistoryAddress(event.WineLabelID, event.Sequence)] = eventData
+	result := tx.label
+	if record != nil {
+		record.HistoryLength = event.Sequence + 1
+		record.HistoryHead = protocol.HistoryHash(eventData)
+		result = record
+	}
+	data, err := protocol.EncodeRecord(*result)
+	if err != nil {
+		return nil, &processor.InternalError{Msg: fmt.Sprint("Failed to encode state: ", err)}
+	}
+	if record != nil {
+		tx.updates[tx.address] = data
+	}
+	if err := self.stageIndexes(tx, record); err != nil {
+		return nil, err
+	}
+	return data, nil
 }
@@ -0,0 +1,371 @@
+package handler
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+	"testing"
+
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/processor_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
+
+	"wine-label-protocol/protocol"
+)
+
+func TestApplySet(t *testing.T) {
+	created := []step{{wineryKey, setLabelInLot("125", testLot), ""}}
+	legacy := setLabel("125")
+	legacy.Print = protocol.PrintEvent{}
+	noPrint := legacy
+	badPosition := setLabel("125")
+	badPosition.Lattitude = "91"
+	badSecret := setLabel("125")
+	badSecret.Secret = protocol.LabelSecret{Salt: "short", Hash: "ab"}
+	moved := setLabelInLot("125", testLot)
+	moved.Print.Facility = "warehouse"
+	moved.Lot = ""
+
+	runCases(t, []handlerCase{
+		{name: "create", step: step{wineryKey, setLabelInLot("125", testLot), ""},
+			check: func(t *testing.T, state *MemoryState) {
+				record := getLabel(t, state, "125")
+				if record.Owner != wineryKey || record.Status != protocol.STATUS_PRINTED ||
+					record.HistoryLength != 1 || record.Facility != "cellar" || record.Operator != "alice" {
+					t.Errorf("create: record = %+v", record)
+				}
+				if lot := getLot(t, state, testLot); lot.LabelCount != 1 {
+					t.Errorf("create: lot has %d labels, want 1", lot.LabelCount)
+				}
+				wantEvents(t, "create", state, protocol.EVENT_CREATED)
+				event := state.Events[0]
+				if event.Attribute(protocol.ATTRIBUTE_LABEL_ID) != "125" || event.Attribute(protocol.ATTRIBUTE_SIGNER) != wineryKey ||
+					event.Attribute(protocol.ATTRIBUTE_ADDRESS) != testNamespace.LabelAddress("125") {
+					t.Errorf("create: event attributes = %v", event.Attributes)
+				}
+				if len(state.Receipts) != 1 {
+					t.Errorf("create: %d receipts, want 1", len(state.Receipts))
+				}
+			}},
+		{name: "create by printer", step: step{printerKey, setLabel("125"), ""}},
+		{name: "create under 1.0 without print event", step: step{wineryKey, legacy, protocol.FAMILY_VERSION_1}},
+		{name: "create by unregistered key", step: step{consumerKey, setLabel("125"), ""}, invalid: true},
+		{name: "create by distributor", step: step{distributorKey, setLabel("125"), ""}, invalid: true},
+		{name: "no print event under 2.0", step: step{wineryKey, noPrint, ""}, invalid: true},
+		{name: "invalid position", step: step{wineryKey, badPosition, ""}, invalid: true},
+		{name: "invalid secret", step: step{wineryKey, badSecret, ""}, invalid: true},
+		{name: "no such lot", step: step{wineryKey, setLabelInLot("125", "lot-1999"), ""}, invalid: true},
+		{name: "update", setup: created, step: step{wineryKey, moved, ""},
+			check: func(t *testing.T, state *MemoryState) {
+				record := getLabel(t, state, "125")
+				if record.Facility != "warehouse" || record.Lot != "" || record.HistoryLength != 2 {
+					t.Errorf("update: record = %+v", record)
+				}
+				if hasState(state, testNamespace.IndexAddress(protocol.FACILITY_INDEX_SPACE, "cellar", "125")) ||
+					!hasState(state, testNamespace.IndexAddress(protocol.FACILITY_INDEX_SPACE, "warehouse", "125")) {
+					t.Error("update: facility index was not moved")
+				}
+				if hasState(state, testNamespace.IndexAddress(protocol.LOT_INDEX_SPACE, testLot, "125")) {
+					t.Error("update: lot index entry was not removed")
+				}
+				if lot := getLot(t, state, testLot); lot.LabelCount != 0 {
+					t.Errorf("update: lot has %d labels, want 0", lot.LabelCount)
+				}
+				wantEvents(t, "update", state, protocol.EVENT_UPDATED)
+			}},
+		{name: "update by another key", setup: created, step: step{printerKey, setLabel("125"), ""}, invalid: true},
+		{name: "update after apply",
+			setup: append(created, step{wineryKey, labelVerb(protocol.VERB_APPLY, "125"), ""}),
+			step:  step{wineryKey, setLabel("125"), ""}, invalid: true},
+		{name: "reuse of a withdrawn ID",
+			setup: append(created, step{wineryKey, labelVerb(protocol.VERB_DELETE, "125"), ""}),
+			step:  step{wineryKey, setLabel("125"), ""}, invalid: true},
+		{name: "lot is full",
+			setup: []step{
+				{wineryKey, setLabelInLot("1", testLot), ""},
+				{wineryKey, setLabelInLot("2", testLot), ""},
+				{wineryKey, setLabelInLot("3", testLot), ""},
+			},
+			step: step{wineryKey, setLabelInLot("4", testLot), ""}, invalid: true},
+		{name: "recalled lot",
+			setup: []step{{regulatorKey, recall(testLot, "cork taint"), ""}},
+			step:  step{wineryKey, setLabelInLot("125", testLot), ""}, invalid: true},
+	})
+}
+
+func TestApplyDelete(t *testing.T) {
+	created := []step{{wineryKey, setLabelInLot("125", testLot), ""}}
+	runCases(t, []handlerCase{
+		{name: "delete", setup: created, step: step{wineryKey, labelVerb(protocol.VERB_DELETE, "125"), ""},
+			check: func(t *testing.T, state *MemoryState) {
+				if hasState(state, testNamespace.LabelAddress("125")) {
+					t.Error("delete: label is still stored")
+				}
+				if !hasState(state, testNamespace.HistoryAddress("125", 0)) || !hasState(state, testNamespace.HistoryAddress("125", 1)) {
+					t.Error("delete: history was not kept")
+				}
+				for _, address := range testNamespace.IndexAddresses(protocol.LabelRecord{
+					WineLabelID: "125", Owner: wineryKey, Facility: "cellar", Lot: testLot}) {
+					if hasState(state, address) {
+						t.Errorf("delete: index entry %v is still stored", address)
+					}
+				}
+				if lot := getLot(t, state, testLot); lot.LabelCount != 0 {
+					t.Errorf("delete: lot has %d labels, want 0", lot.LabelCount)
+				}
+				wantEvents(t, "delete", state, protocol.EVENT_DELETED)
+			}},
+		{name: "delete by another key", setup: created, step: step{printerKey, labelVerb(protocol.VERB_DELETE, "125"), ""}, invalid: true},
+		{name: "no such label", step: step{wineryKey, labelVerb(protocol.VERB_DELETE, "125"), ""}, invalid: true},
+	})
+}
+
+func TestApplyTransition(t *testing.T) {
+	created := []step{{wineryKey, setLabel("125"), ""}}
+	voided := append(created, step{wineryKey, labelVerb(protocol.VERB_VOID, "125"), ""})
+	runCases(t, []handlerCase{
+		{name: "apply", setup: created, step: step{wineryKey, labelVerb(protocol.VERB_APPLY, "125"), ""},
+			check: func(t *testing.T, state *MemoryState) {
+				if record := getLabel(t, state, "125"); record.Status != protocol.STATUS_APPLIED || record.HistoryLength != 2 {
+					t.Errorf("apply: record = %+v", record)
+				}
+				wantEvents(t, "apply", state, protocol.EVENT_UPDATED)
+			}},
+		{name: "void", setup: created, step: step{wineryKey, labelVerb(protocol.VERB_VOID, "125"), ""}},
+		{name: "skip to sell", setup: created, step: step{wineryKey, labelVerb(protocol.VERB_SELL, "125"), ""}, invalid: true},
+		{name: "after void", setup: voided, step: step{wineryKey, labelVerb(protocol.VERB_APPLY, "125"), ""}, invalid: true},
+		{name: "by another key", setup: created, step: step{printerKey, labelVerb(protocol.VERB_APPLY, "125"), ""}, invalid: true},
+		{name: "no such label", step: step{wineryKey, labelVerb(protocol.VERB_APPLY, "125"), ""}, invalid: true},
+	})
+}
+
+func TestApplyTransfer(t *testing.T) {
+	created := []step{{wineryKey, setLabel("125"), ""}}
+	offered := append(created, step{wineryKey, offer("125", distributorKey), ""})
+	runCases(t, []handlerCase{
+		{name: "offer", setup: created, step: step{wineryKey, offer("125", distributorKey), ""},
+			check: func(t *testing.T, state *MemoryState) {
+				if record := getLabel(t, state, "125"); record.PendingOwner != distributorKey || record.Owner != wineryKey {
+					t.Errorf("offer: record = %+v", record)
+				}
+			}},
+		{name: "offer to owner", setup: created, step: step{wineryKey, offer("125", wineryKey), ""}, invalid: true},
+		{name: "offer to nobody", setup: created, step: step{wineryKey, offer("125", ""), ""}, invalid: true},
+		{name: "offer twice", setup: offered, step: step{wineryKey, offer("125", consumerKey), ""}, invalid: true},
+		{name: "offer by another key", setup: created, step: step{printerKey, offer("125", consumerKey), ""}, invalid: true},
+		{name: "accept", setup: offered, step: step{distributorKey, labelVerb(protocol.VERB_ACCEPT, "125"), ""},
+			check: func(t *testing.T, state *MemoryState) {
+				if record := getLabel(t, state, "125"); record.Owner != distributorKey || record.PendingOwner != "" {
+					t.Errorf("accept: record = %+v", record)
+				}
+				if hasState(state, testNamespace.IndexAddress(protocol.OWNER_INDEX_SPACE, wineryKey, "125")) ||
+					!hasState(state, testNamespace.IndexAddress(protocol.OWNER_INDEX_SPACE, distributorKey, "125")) {
+					t.Error("accept: owner index was not moved")
+				}
+				wantEvents(t, "accept", state, protocol.EVENT_TRANSFERRED)
+			}},
+		{name: "accept by another key", setup: offered, step: step{consumerKey, labelVerb(protocol.VERB_ACCEPT, "125"), ""}, invalid: true},
+		{name: "accept without offer", setup: created, step: step{distributorKey, labelVerb(protocol.VERB_ACCEPT, "125"), ""}, invalid: true},
+		{name: "cancel by owner", setup: offered, step: step{wineryKey, labelVerb(protocol.VERB_CANCEL, "125"), ""},
+			check: func(t *testing.T, state *MemoryState) {
+				if record := getLabel(t, state, "125"); record.PendingOwner != "" {
+					t.Errorf("cancel: record = %+v", record)
+				}
+			}},
+		{name: "cancel by recipient", setup: offered, step: step{distributorKey, labelVerb(protocol.VERB_CANCEL, "125"), ""}},
+		{name: "cancel by another key", setup: offered, step: step{consumerKey, labelVerb(protocol.VERB_CANCEL, "125"), ""}, invalid: true},
+		{name: "cancel without offer", setup: created, step: step{wineryKey, labelVerb(protocol.VERB_CANCEL, "125"), ""}, invalid: true},
+	})
+}
+
+func TestApplyMalformed(t *testing.T) {
+	state := fixture(t)
+	handler := NewWineLabelHandler(protocol.FAMILY_NAME, DefaultPolicy(protocol.FAMILY_NAME))
+	header := &transaction_pb2.TransactionHeader{FamilyVersion: protocol.FAMILY_VERSION_2, SignerPublicKey: wineryKey}
+	for name, payload := range map[string][]byte{
+		"no payload":  nil,
+		"not encoded": []byte("125,set"),
+	} {
+		wantInvalid(t, name, handler.ApplyState(&processor_pb2.TpProcessRequest{Header: header, Payload: payload}, state))
+	}
+	for name, s := range map[string]step{
+		"no label ID":  {wineryKey, labelVerb(protocol.VERB_APPLY, ""), ""},
+		"unknown verb": {wineryKey, labelVerb("relabel", "125"), ""},
+	} {
+		wantInvalid(t, name, applyStep(state, s))
+	}
+}
+
+// TestLegacyAddress checks that labels stored by the original processor
+// are still found, so that their IDs cannot be taken again.
+func TestLegacyAddress(t *testing.T) {
+	state := fixture(t)
+	setLegacy(t, state, "125", legacyLabel)
+	setLegacy(t, state, "W-0001", legacyLabel)
+	setLegacy(t, state, "126", legacyDeleted)
+	for name, s := range map[string]step{
+		"set over a legacy label":  {wineryKey, setLabel("125"), ""},
+		"mint over a legacy label": {wineryKey, mint(1, 2, ""), ""},
+	} {
+		wantInvalid(t, name, applyStep(state.Copy(), s))
+	}
+	if err := applyStep(state, step{wineryKey, setLabel("126"), ""}); err != nil {
+		t.Errorf("Apply rejected a label deleted by the original processor: %v", err)
+	}
+}
+
+// TestLegacyUpgrade checks that a label stored by the original processor
+// is held by the admins until handed over, and moves to its current
+// address when it changes.
+func TestLegacyUpgrade(t *testing.T) {
+	state := fixture(t)
+	setLegacy(t, state, "125", legacyLabel)
+	wantInvalid(t, "apply by the winery before handover", applyStep(state.Copy(), step{wineryKey, labelVerb(protocol.VERB_APPLY, "125"), ""}))
+	mustApply(t, state, step{adminKey, offer("125", wineryKey), ""})
+	position, _ := protocol.ParseGeoPoint("44.837789", "-0.57918")
+	if !hasState(state, testNamespace.GeoIndexAddress(position, "125")) {
+		t.Error("Upgraded label was not indexed")
+	}
+	mustApply(t, state, step{wineryKey, labelVerb(protocol.VERB_ACCEPT, "125"), ""})
+	if hasState(state, testNamespace.LegacyLabelAddress("125")) {
+		t.Error("Upgraded label was left at its legacy address")
+	}
+	record := getLabel(t, state, "125")
+	if record.Owner != wineryKey || record.Position != position || record.PrintedAt != "Bordeaux" {
+		t.Errorf("Upgraded label = %+v", record)
+	}
+
+	garbled, err := protocol.EncodeCBOR(protocol.Payload{WineLabelID: "127", Lattitude: "east", Longitude: "north"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.SetState(map[string][]byte{testNamespace.LegacyLabelAddress("127"): garbled})
+	wantInvalid(t, "offer of a garbled legacy label", applyStep(state, step{adminKey, offer("127", wineryKey), ""}))
+}
+
+// TestSetAddresses checks every address a new label is written to, since
+// the client must list them all as outputs.
+func TestSetAddresses(t *testing.T) {
+	state := fixture(t)
+	before := make(map[string]bool)
+	for _, address := range state.Addresses() {
+		before[address] = true
+	}
+	mustApply(t, state, step{wineryKey, setLabelInLot("125", testLot), ""})
+	var written []string
+	for _, address := range state.Addresses() {
+		if !before[address] {
+			written = append(written, address)
+		}
+	}
+	position, _ := protocol.ParseGeoPoint(testLatitude, testLongitude)
+	want := []string{
+		testNamespace.LabelAddress("125"),
+		testNamespace.HistoryAddress("125", 0),
+		testNamespace.IndexAddress(protocol.OWNER_INDEX_SPACE, wineryKey, "125"),
+		testNamespace.IndexAddress(protocol.FACILITY_INDEX_SPACE, "cellar", "125"),
+		testNamespace.IndexAddress(protocol.LOT_INDEX_SPACE, testLot, "125"),
+		testNamespace.GeoIndexAddress(position, "125"),
+	}
+	sort.Strings(want)
+	if !reflect.DeepEqual(written, want) {
+		t.Errorf("set wrote %v, want %v", written, want)
+	}
+}
+
+// TestTenants checks that a tenant has its own namespace and admins.
+func TestTenants(t *testing.T) {
+	family, _ := protocol.TenantFamilyName("bordeaux")
+	tenant := NewWineLabelHandler(family, DefaultPolicy(family))
+	namespace := protocol.NewNamespace(family)
+	if tenant.FamilyName() != family || !reflect.DeepEqual(tenant.Namespaces(), []string{string(namespace)}) {
+		t.Fatalf("Tenant handler serves %v in %v", tenant.FamilyName(), tenant.Namespaces())
+	}
+
+	state := fixture(t)
+	register := step{adminKey, registerOrg("chateau", protocol.ORG_WINERY, wineryKey), ""}
+	if err := applyWith(tenant, state, register); err == nil {
+		t.Error("Tenant accepted an admin of the default family")
+	}
+	setSetting(t, state, protocol.AdminsSetting(family), adminKey)
+	if err := applyWith(tenant, state, register); err != nil {
+		t.Fatalf("Tenant rejected its own admin: %v", err)
+	}
+	if !hasState(state, namespace.OrganisationAddress("chateau")) {
+		t.Error("Tenant did not register the organisation in its own namespace")
+	}
+
+	if err := applyWith(tenant, state, step{wineryKey, setLabel("125"), ""}); err != nil {
+		t.Errorf("Tenant rejected a label: %v", err)
+	}
+	if !hasState(state, namespace.LabelAddress("125")) || hasState(state, testNamespace.LabelAddress("125")) {
+		t.Error("Tenant did not store the label in its own namespace")
+	}
+}
+
+func TestPolicyHash(t *testing.T) {
+	policy := DefaultPolicy(protocol.FAMILY_NAME)
+	if policy.Hash() != DefaultPolicy(protocol.FAMILY_NAME).Hash() {
+		t.Error("Identical policies hash differently")
+	}
+	bounds, _ := protocol.ParseBoundingBox("42,-5,51,8")
+	changes := map[string]func(policy *Policy){
+		"payload size": func(policy *Policy) { policy.MaxPayloadSize = 1024 },
+		"pattern":      func(policy *Policy) { policy.LabelIDPattern = regexp.MustCompile(`^W-\d{4}$`) },
+		"bounds":       func(policy *Policy) { policy.Bounds = &bounds },
+		"admins":       func(policy *Policy) { policy.AdminsSetting = protocol.AdminsSetting("wine-label-napa") },
+	}
+	for name, change := range changes {
+		changed := policy
+		change(&changed)
+		if changed.Hash() == policy.Hash() {
+			t.Errorf("%s: changing the policy kept its hash", name)
+		}
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	bounds, err := protocol.ParseBoundingBox("42,-5,51,8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy := DefaultPolicy(protocol.FAMILY_NAME)
+	policy.LabelIDPattern = regexp.MustCompile(`^W-\d{4}$`)
+	policy.Bounds = &bounds
+	handler := NewWineLabelHandler(protocol.FAMILY_NAME, policy)
+	base := fixture(t)
+
+	far := setLabel("W-0100")
+	far.Longitude, far.Lattitude = farLongitude, farLatitude
+	farMint := mint(1, 2, "")
+	farMint.Mint.Longitude, farMint.Mint.Lattitude = farLongitude, farLatitude
+	otherPrefix := mint(1, 2, "")
+	otherPrefix.Mint.Prefix = "X-"
+	for name, s := range map[string]step{
+		"set with ID out of pattern":  {wineryKey, setLabel("125"), ""},
+		"mint with ID out of pattern": {wineryKey, otherPrefix, ""},
+		"set out of bounds":           {wineryKey, far, ""},
+		"mint out of bounds":          {wineryKey, farMint, ""},
+	} {
+		wantInvalid(t, name, applyWith(handler, base.Copy(), s))
+	}
+	for name, s := range map[string]step{
+		"set":  {wineryKey, setLabel("W-0100"), ""},
+		"mint": {wineryKey, mint(1, 2, ""), ""},
+	} {
+		if err := applyWith(handler, base.Copy(), s); err != nil {
+			t.Errorf("%s: Apply: %v", name, err)
+		}
+	}
+
+	policy = DefaultPolicy(protocol.FAMILY_NAME)
+	policy.MaxPayloadSize = 16
+	handler = NewWineLabelHandler(protocol.FAMILY_NAME, policy)
+	request, err := newRequest(handler, step{wineryKey, setLabel("125"), ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = handler.apply(request, base.Copy())
+	if outcome, reason := Outcome(err); outcome != OUTCOME_INVALID || reason != REASON_MALFORMED {
+		t.Errorf("Oversized payload: Apply error = %v, want a malformed transaction", err)
+	}
+}
@@ -0,0 +1,395 @@
+package handler
+
+import (
+	"encoding/hex"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/sawtooth-sdk-go/processor"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/processor_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/setting_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
+
+	"wine-label-protocol/protocol"
+)
+
+var testNamespace = protocol.NewNamespace(protocol.FAMILY_NAME)
+
+// The keys of the test fixture: the family admin, a regulator, a key of
+// each registered organisation and a consumer's key registered nowhere.
+var (
+	adminKey       = testKey("01")
+	regulatorKey   = testKey("02")
+	wineryKey      = testKey("03")
+	printerKey     = testKey("04")
+	distributorKey = testKey("05")
+	consumerKey    = testKey("06")
+	otherKey       = testKey("07")
+)
+
+const (
+	testLot        = "lot-2019"
+	testLotBottles = 3
+	testSalt       = "00112233445566778899aabbccddeeff"
+	testSecret     = "scratch"
+	// Bordeaux, and New York about 5800 km away.
+	testLongitude = "-0.57918"
+	testLatitude  = "44.837789"
+	farLongitude  = "-74.006"
+	farLatitude   = "40.7128"
+)
+
+func testKey(suffix string) string {
+	return "02" + strings.Repeat("ab", 31) + suffix
+}
+
+// step is one transaction: a payload signed by signer and sent under a
+// family version, FAMILY_VERSION_2 if none is given.
+type step struct {
+	signer  string
+	payload protocol.WineLabelPayload
+	version string
+}
+
+// handlerCase applies its setup to a copy of the fixture, then its own
+// step, which must be rejected as invalid or else pass check.
+type handlerCase struct {
+	name    string
+	setup   []step
+	step    step
+	invalid bool
+	check   func(t *testing.T, state *MemoryState)
+}
+
+func applyStep(state State, s step) error {
+	return applyWith(NewWineLabelHandler(protocol.FAMILY_NAME, DefaultPolicy(protocol.FAMILY_NAME)), state, s)
+}
+
+func applyWith(handler *WineLabelHandler, state State, s step) error {
+	request, err := newRequest(handler, s)
+	if err != nil {
+		return err
+	}
+	if memory, ok := state.(*MemoryState); ok {
+		state = memory.Context(request.Header)
+	}
+	return handler.ApplyState(request, state)
+}
+
+// newRequest builds the request the validator sends handler for s, with
+// the inputs and outputs the client declares.
+func newRequest(handler *WineLabelHandler, s step) (*processor_pb2.TpProcessRequest, error) {
+	version := s.version
+	if version == "" {
+		version = protocol.FAMILY_VERSION_2
+	}
+	data, err := protocol.EncodePayload(version, s.payload)
+	if err != nil {
+		return nil, err
+	}
+	inputs, outputs := protocol.TransactionAddresses(handler.FamilyName(), s.signer, s.payload)
+	return &processor_pb2.TpProcessRequest{
+		Header: &transaction_pb2.TransactionHeader{
+			FamilyName:      handler.FamilyName(),
+			FamilyVersion:   version,
+			SignerPublicKey: s.signer,
+			Inputs:          inputs,
+			Outputs:         outputs,
+		},
+		Payload:   data,
+		Signature: protocol.Hexdigest(s.signer + string(data))[:64],
+	}, nil
+}
+
+func mustApply(t *testing.T, state State, s step) {
+	t.Helper()
+	if err := applyStep(state, s); err != nil {
+		t.Fatalf("Apply(%v): %v", s.payload.Verb, err)
+	}
+}
+
+// fixture returns a state with the admin and regulator settings, a winery,
+// a printer and a distributor, and a lot of testLotBottles bottles.
+func fixture(t *testing.T) *MemoryState {
+	t.Helper()
+	state := NewMemoryState()
+	setSetting(t, state, protocol.ADMINS_SETTING, adminKey)
+	setSetting(t, state, protocol.REGULATORS_SETTING, regulatorKey)
+	mustApply(t, state, step{adminKey, registerOrg("chateau", protocol.ORG_WINERY, wineryKey), ""})
+	mustApply(t, state, step{adminKey, registerOrg("press", protocol.ORG_PRINTER, printerKey), ""})
+	mustApply(t, state, step{adminKey, registerOrg("shipper", protocol.ORG_DISTRIBUTOR, distributorKey), ""})
+	mustApply(t, state, step{wineryKey, createLot(testLot, testLotBottles), ""})
+	return state
+}
+
+func setSetting(t *testing.T, state *MemoryState, key string, value string) {
+	t.Helper()
+	data, err := proto.Marshal(&setting_pb2.Setting{
+		Entries: []*setting_pb2.Setting_Entry{{Key: key, Value: value}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.SetState(map[string][]byte{protocol.SettingAddress(key): data})
+}
+
+func runCases(t *testing.T, cases []handlerCase) {
+	base := fixture(t)
+	for _, c := range cases {
+		state := base.Copy()
+		for _, s := range c.setup {
+			if err := applyStep(state, s); err != nil {
+				t.Fatalf("%s: setup %v: %v", c.name, s.payload.Verb, err)
+			}
+		}
+		state.Events, state.Receipts = nil, nil
+		err := applyStep(state, c.step)
+		if c.invalid {
+			wantInvalid(t, c.name, err)
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: Apply: %v", c.name, err)
+			continue
+		}
+		if c.check != nil {
+			c.check(t, state)
+		}
+	}
+}
+
+// wantInvalid fails the test unless err rejects a transaction as invalid.
+func wantInvalid(t *testing.T, name string, err error) {
+	t.Helper()
+	if _, ok := err.(*processor.InvalidTransactionError); !ok {
+		t.Errorf("%s: Apply error = %v, want an invalid transaction", name, err)
+	}
+}
+
+func printEvent(at time.Time) protocol.PrintEvent {
+	return protocol.PrintEvent{
+		Time:     at.UTC().Format(time.RFC3339),
+		Printer:  "press-1",
+		Facility: "cellar",
+		Operator: "alice",
+	}
+}
+
+func setLabel(id string) protocol.WineLabelPayload {
+	return protocol.WineLabelPayload{
+		Payload: protocol.Payload{
+			WineLabelID: id,
+			PrintedAt:   "Bordeaux",
+			Longitude:   testLongitude,
+			Lattitude:   testLatitude,
+			Print:       printEvent(time.Now().Add(-time.Minute)),
+		},
+		Verb: protocol.VERB_SET,
+	}
+}
+
+func setLabelInLot(id string, lotID string) protocol.WineLabelPayload {
+	payload := setLabel(id)
+	payload.Lot = lotID
+	return payload
+}
+
+func setLabelWithSecret(id string) protocol.WineLabelPayload {
+	payload := setLabel(id)
+	payload.Secret = protocol.LabelSecret{Salt: testSalt, Hash: protocol.SecretHash(testSalt, testSecret)}
+	return payload
+}
+
+func labelVerb(verb string, id string) protocol.WineLabelPayload {
+	return protocol.WineLabelPayload{Payload: protocol.Payload{WineLabelID: id}, Verb: verb}
+}
+
+func offer(id string, recipient string) protocol.WineLabelPayload {
+	payload := labelVerb(protocol.VERB_OFFER, id)
+	payload.Recipient = recipient
+	return payload
+}
+
+func registerOrg(orgID string, orgType protocol.OrgType, keys ...string) protocol.WineLabelPayload {
+	return protocol.WineLabelPayload{
+		Verb:         protocol.VERB_REGISTER_ORG,
+		Organisation: protocol.OrgPayload{OrgID: orgID, Name: orgID, Type: orgType, Keys: keys},
+	}
+}
+
+func updateOrg(orgID string, orgType protocol.OrgType, keys ...string) protocol.WineLabelPayload {
+	payload := registerOrg(orgID, orgType, keys...)
+	payload.Verb = protocol.VERB_UPDATE_ORG
+	return payload
+}
+
+func createLot(lotID string, bottles uint64) protocol.WineLabelPayload {
+	return protocol.WineLabelPayload{
+		Verb: protocol.VERB_CREATE_LOT,
+		LotDetails: protocol.LotPayload{
+			LotID:       lotID,
+			Vintage:     2019,
+			Varietals:   []protocol.Varietal{{Grape: "Merlot", Percent: 100}},
+			Appellation: "Saint-Emilion",
+			BottleCount: bottles,
+		},
+	}
+}
+
+func recall(lotID string, reason string) protocol.WineLabelPayload {
+	return protocol.WineLabelPayload{
+		Verb:       protocol.VERB_RECALL,
+		LotDetails: protocol.LotPayload{LotID: lotID},
+		Reason:     reason,
+		ClaimedAt:  "2020-01-02T03:04:05Z",
+	}
+}
+
+func mint(first uint64, count uint64, lotID string) protocol.WineLabelPayload {
+	return protocol.WineLabelPayload{
+		Verb: protocol.VERB_MINT,
+		Mint: protocol.MintPayload{
+			Prefix:    "W-",
+			First:     first,
+			Count:     count,
+			Digits:    4,
+			Print:     printEvent(time.Now().Add(-time.Minute)),
+			Longitude: testLongitude,
+			Lattitude: testLatitude,
+			Lot:       lotID,
+		},
+	}
+}
+
+func scan(id string, longitude string, latitude string, scannedAt string) protocol.WineLabelPayload {
+	payload := labelVerb(protocol.VERB_SCAN, id)
+	payload.Longitude = longitude
+	payload.Lattitude = latitude
+	payload.ClaimedAt = scannedAt
+	return payload
+}
+
+func claim(id string, secret string) protocol.WineLabelPayload {
+	payload := labelVerb(protocol.VERB_CLAIM, id)
+	payload.RevealedSecret = secret
+	payload.ClaimedAt = "2020-01-02T03:04:05Z"
+	return payload
+}
+
+func getLabel(t *testing.T, state *MemoryState, id string) protocol.LabelRecord {
+	t.Helper()
+	address := testNamespace.LabelAddress(id)
+	results, _ := state.GetState([]string{address})
+	data, exists := results[address]
+	if !exists {
+		t.Fatalf("No wine label %v", id)
+	}
+	record, err := protocol.DecodeLabelRecord(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return record
+}
+
+func getLot(t *testing.T, state *MemoryState, lotID string) protocol.Lot {
+	t.Helper()
+	var lot protocol.Lot
+	exists, err := getState(state, testNamespace.LotAddress(lotID), &lot)
+	if err != nil || !exists {
+		t.Fatalf("No lot %v: %v", lotID, err)
+	}
+	return lot
+}
+
+func getOrganisation(t *testing.T, state *MemoryState, orgID string) protocol.Organisation {
+	t.Helper()
+	var org protocol.Organisation
+	exists, err := getState(state, testNamespace.OrganisationAddress(orgID), &org)
+	if err != nil || !exists {
+		t.Fatalf("No organisation %v: %v", orgID, err)
+	}
+	return org
+}
+
+func hasState(state *MemoryState, address string) bool {
+	results, _ := state.GetState([]string{address})
+	_, exists := results[address]
+	return exists
+}
+
+// changedAddresses returns the addresses whose data differs between two
+// states, in order.
+func changedAddresses(before *MemoryState, after *MemoryState) []string {
+	seen := make(map[string]bool)
+	var changed []string
+	for _, address := range append(before.Addresses(), after.Addresses()...) {
+		was, _ := before.GetState([]string{address})
+		is, _ := after.GetState([]string{address})
+		if !seen[address] && !reflect.DeepEqual(was, is) {
+			changed = append(changed, address)
+		}
+		seen[address] = true
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// declared reports whether address is one of, or under one of, the
+// addresses a transaction header declares.
+func declared(address string, addresses []string) bool {
+	for _, prefix := range addresses {
+		if strings.HasPrefix(address, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func eventTypes(state *MemoryState) []string {
+	var types []string
+	for _, event := range state.Events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+func wantEvents(t *testing.T, name string, state *MemoryState, want ...string) {
+	t.Helper()
+	if got := eventTypes(state); !reflect.DeepEqual(got, want) {
+		t.Errorf("%s: events = %v, want %v", name, got, want)
+	}
+}
+
+// wantAlert checks that the transaction's last event is the alert
+// eventType, giving reason, or any reason if reason is empty.
+func wantAlert(t *testing.T, name string, state *MemoryState, eventType string, reason string) {
+	t.Helper()
+	if len(state.Events) == 0 || state.Events[len(state.Events)-1].Type != eventType {
+		t.Errorf("%s: events = %v, want them to end with %v", name, eventTypes(state), eventType)
+		return
+	}
+	got := state.Events[len(state.Events)-1].Attribute(protocol.ATTRIBUTE_REASON)
+	if got == "" || (reason != "" && got != reason) {
+		t.Errorf("%s: %v reason = %q, want %q", name, eventType, got, reason)
+	}
+}
+
+// Records the original processor stored for label 125, printed in
+// Bordeaux, and for a label it deleted.
+const (
+	legacyLabel   = "a46b57696e654c6162656c494463313235695072696e746564417468426f726465617578694c6f6e6769747564656934342e383337373839694c6174746974756465682d302e3537393138"
+	legacyDeleted = "a46b57696e654c6162656c494460695072696e746564417460694c6f6e67697475646560694c617474697475646560"
+)
+
+func setLegacy(t *testing.T, state *MemoryState, id string, record string) {
+	t.Helper()
+	data, err := hex.DecodeString(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.SetState(map[string][]byte{testNamespace.LegacyLabelAddress(id): data})
+}
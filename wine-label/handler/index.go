@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/sawtooth-sdk-go/processor"
+
+	"wine-label-protocol/protocol"
+)
+
+// stageIndexes brings the index entries of the label of tx in line with
+// its new record: entries for new values are added to tx.updates and
+// entries for values the label no longer has are queued for deletion. A
+// nil record removes every entry. A label still at its legacy address has
+// no entries yet, so all of its entries are new.
+func (self *WineLabelHandler) stageIndexes(tx *labelTransaction, record *protocol.LabelRecord) error {
+	current := make(map[string]bool)
+	if record != nil {
+		for _, address := range self.namespace.IndexAddresses(*record) {
+			current[address] = true
+		}
+	}
+	previous := make(map[string]bool)
+	if tx.label != nil && !tx.legacy {
+		for _, address := range self.namespace.IndexAddresses(*tx.label) {
+			previous[address] = true
+			if !current[address] {
+				tx.deletions = append(tx.deletions, address)
+			}
+		}
+	}
+	if len(current) == 0 {
+		return nil
+	}
+	data, err := protocol.EncodeRecord(protocol.IndexEntry{WineLabelID: tx.payload.WineLabelID})
+	if err != nil {
+		return &processor.InternalError{Msg: fmt.Sprint("Failed to encode index entry: ", err)}
+	}
+	for address := range current {
+		if !previous[address] {
+			tx.updates[address] = data
+		}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"wine-label-protocol/protocol"
+)
+
+// payloadFields tags log entries with the verb of a payload and the IDs it
+// names.
+func payloadFields(payload protocol.WineLabelPayload) logrus.Fields {
+	fields := logrus.Fields{"verb": payload.Verb}
+	ids := map[string]string{
+		"label_id": payload.WineLabelID,
+		"lot_id":   payload.LotDetails.LotID,
+		"org_id":   payload.Organisation.OrgID,
+	}
+	for key, id := range ids {
+		if id != "" {
+			fields[key] = id
+		}
+	}
+	return fields
+}
+
+// logOutcome logs how a transaction ended. Rejections are logged at info
+// level, so that a failed submission can be traced to its reason without
+// debug logging; internal errors, which the validator retries, as errors.
+func logOutcome(log *logrus.Entry, err error, elapsed time.Duration) {
+	outcome, reason := Outcome(err)
+	log = log.WithFields(logrus.Fields{"outcome": outcome, "elapsed": elapsed})
+	switch outcome {
+	case OUTCOME_OK:
+		log.Debug("Transaction applied")
+	case OUTCOME_INVALID:
+		log.WithError(err).WithField("reason", reason).Info("Transaction rejected")
+	default:
+		log.WithError(err).Error("Transaction failed")
+	}
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+
+	"wine-label-protocol/protocol"
+)
+
+func TestApplyLogs(t *testing.T) {
+	hook := test.NewLocal(logger)
+	defer hook.Reset()
+	level := logger.GetLevel()
+	logger.SetLevel(logrus.InfoLevel)
+	defer logger.SetLevel(level)
+
+	state := fixture(t)
+	mustApply(t, state, step{wineryKey, setLabelWithSecret("125"), ""})
+	hook.Reset()
+	applyStep(state, step{consumerKey, claim("125", "guess"), ""})
+	applyStep(state, step{otherKey, labelVerb(protocol.VERB_DELETE, "125"), ""})
+
+	entries := hook.AllEntries()
+	if len(entries) != 2 {
+		t.Fatalf("Logged %d entries, want the counterfeit signal and the rejection", len(entries))
+	}
+	want := []map[string]interface{}{
+		{"signer": consumerKey, "verb": protocol.VERB_CLAIM},
+		{"signer": otherKey, "verb": protocol.VERB_DELETE},
+	}
+	for i, entry := range entries {
+		if entry.Data["signature"] == "" {
+			t.Errorf("%q is not tagged with the transaction signature", entry.Message)
+		}
+		want[i]["family"] = protocol.FAMILY_NAME
+		want[i]["label_id"] = "125"
+		for key, value := range want[i] {
+			if entry.Data[key] != value {
+				t.Errorf("%q: %v = %v, want %v", entry.Message, key, entry.Data[key], value)
+			}
+		}
+	}
+	if entries[0].Level != logrus.WarnLevel {
+		t.Errorf("Counterfeit signal logged at %v", entries[0].Level)
+	}
+	rejection := entries[1]
+	if rejection.Level != logrus.InfoLevel || rejection.Data["outcome"] != OUTCOME_INVALID ||
+		rejection.Data["reason"] != REASON_UNAUTHORIZED || rejection.Data[logrus.ErrorKey] == nil {
+		t.Errorf("Rejection logged as %v %q %v", rejection.Level, rejection.Message, rejection.Data)
+	}
+}
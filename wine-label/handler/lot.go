@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hyperledger/sawtooth-sdk-go/processor"
+
+	"wine-label-protocol/protocol"
+)
+
+// applyCreateLot declares a lot on behalf of the winery the signer is
+// registered to.
+func (self *WineLabelHandler) applyCreateLot(context State, signer string, payload protocol.WineLabelPayload) error {
+	org, err := self.getSignerOrganisation(context, signer)
+	if err != nil {
+		return err
+	}
+	if org == nil || org.Type != protocol.ORG_WINERY {
+		return reject(REASON_UNAUTHORIZED,
+			fmt.Sprintf("Cannot create lot %v: signer %v is not registered to a winery",
+				payload.LotDetails.LotID, signer))
+	}
+	lot, err := protocol.NewLot(payload.LotDetails, org.OrgID)
+	if err != nil {
+		return reject(REASON_VALIDATION, err.Error())
+	}
+	address := self.namespace.LotAddress(lot.LotID)
+	var existing protocol.Lot
+	exists, err := getState(context, address, &existing)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return reject(REASON_CONFLICT, fmt.Sprintf("Lot %v already exists", lot.LotID))
+	}
+
+	data, err := protocol.EncodeRecord(lot)
+	if err != nil {
+		return &processor.InternalError{Msg: fmt.Sprint("Failed to encode lot: ", err)}
+	}
+	addresses, err := context.SetState(map[string][]byte{address: data})
+	if err != nil {
+		return err
+	}
+	if len(addresses) == 0 {
+		return &processor.InternalError{Msg: "No addresses in set response"}
+	}
+	return context.AddReceiptData(data)
+}
+
+// applyRecall recalls a lot on behalf of its producer or a regulator. The
+// lot's labels are not rewritten; readers and scans look the recall up on
+// the lot.
+func (self *WineLabelHandler) applyRecall(context State, signer string, payload protocol.WineLabelPayload) error {
+	lotID := payload.LotDetails.LotID
+	address := self.namespace.LotAddress(lotID)
+	var lot protocol.Lot
+	exists, err := getState(context, address, &lot)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return reject(REASON_NOT_FOUND, fmt.Sprintf("Cannot recall lot %v: no such lot", lotID))
+	}
+	if lot.Recall != nil {
+		return reject(REASON_CONFLICT, fmt.Sprintf("Cannot recall lot %v: already %v", lotID, *lot.Recall))
+	}
+	regulator, err := self.isRegulator(context, signer)
+	if err != nil {
+		return err
+	}
+	if !regulator {
+		org, err := self.getSignerOrganisation(context, signer)
+		if err != nil {
+			return err
+		}
+		if org == nil || org.OrgID != lot.Producer {
+			return reject(REASON_UNAUTHORIZED,
+				fmt.Sprintf("Cannot recall lot %v: signer %v is neither registered to its producer nor listed in %v",
+					lotID, signer, self.policy.RegulatorsSetting))
+		}
+	}
+	recall, err := protocol.NewRecall(payload.Reason, payload.ClaimedAt, signer)
+	if err != nil {
+		return reject(REASON_VALIDATION, fmt.Sprintf("Cannot recall lot %v: %v", lotID, err))
+	}
+	lot.Recall = &recall
+
+	data, err := protocol.EncodeRecord(lot)
+	if err != nil {
+		return &processor.InternalError{Msg: fmt.Sprint("Failed to encode lot: ", err)}
+	}
+	addresses, err := context.SetState(map[string][]byte{address: data})
+	if err != nil {
+		return err
+	}
+	if len(addresses) == 0 {
+		return &processor.InternalError{Msg: "No addresses in set response"}
+	}
+	attributes := []processor.Attribute{
+		{Key: protocol.ATTRIBUTE_LOT_ID, Value: lotID},
+		{Key: protocol.ATTRIBUTE_SIGNER, Value: signer},
+		{Key: protocol.ATTRIBUTE_ADDRESS, Value: address},
+		{Key: protocol.ATTRIBUTE_REASON, Value: recall.Reason},
+	}
+	if err := context.AddEvent(protocol.EVENT_LOT_RECALLED, attributes, data); err != nil {
+		return err
+	}
+	return context.AddReceiptData(data)
+}
+
+// moveLot stages the lot counts for the label of tx moving from one lot to
+// another. Either lot may be empty.
+func (self *WineLabelHandler) moveLot(tx *labelTransaction, from string, to string) error {
+	if from == to {
+		return nil
+	}
+	attached := make(map[string]uint64)
+	detached := make(map[string]uint64)
+	if to != "" {
+		attached[to] = 1
+	}
+	if from != "" {
+		detached[from] = 1
+	}
+	return self.adjustLots(tx.context, tx.signer, attached, detached, tx.updates)
+}
+
+// adjustLots stages the lots whose label counts change as labels are
+// attached to or detached from them. Only keys registered to a lot's
+// producer or to a printer may attach labels, and never more than the lot
+// has bottles, and none to a recalled lot.
+func (self *WineLabelHandler) adjustLots(context State, signer string, attached map[string]uint64, detached map[string]uint64, updates map[string][]byte) error {
+	lotIDs := make([]string, 0, len(attached)+len(detached))
+	for lotID := range attached {
+		lotIDs = append(lotIDs, lotID)
+	}
+	for lotID := range detached {
+		if _, ok := attached[lotID]; !ok {
+			lotIDs = append(lotIDs, lotID)
+		}
+	}
+	sort.Strings(lotIDs)
+
+	var org *protocol.Organisation
+	for _, lotID := range lotIDs {
+		address := self.namespace.LotAddress(lotID)
+		var lot protocol.Lot
+		exists, err := getState(context, address, &lot)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return reject(REASON_NOT_FOUND, fmt.Sprintf("No such lot %v", lotID))
+		}
+
+		if count := detached[lotID]; count > lot.LabelCount {
+			return &processor.InternalError{
+				Msg: fmt.Sprintf("Lot %v has %d labels, cannot detach %d", lotID, lot.LabelCount, count),
+			}
+		}
+		lot.LabelCount -= detached[lotID]
+
+		if count := attached[lotID]; count > 0 {
+			if lot.Recall != nil {
+				return reject(REASON_CONFLICT,
+					fmt.Sprintf("Cannot attach labels to lot %v: lot was %v", lotID, *lot.Recall))
+			}
+			if org == nil {
+				org, err = self.getSignerOrganisation(context, signer)
+				if err != nil {
+					return err
+				}
+			}
+			if org == nil || (org.OrgID != lot.Producer && org.Type != protocol.ORG_PRINTER) {
+				return reject(REASON_UNAUTHORIZED,
+					fmt.Sprintf("Cannot attach labels to lot %v: signer %v is registered to neither its producer nor a printer",
+						lotID, signer))
+			}
+			if count > lot.Remaining() {
+				return reject(REASON_CONFLICT,
+					fmt.Sprintf("Cannot attach %d labels to lot %v: only %d of its %d bottles are unlabelled",
+						count, lotID, lot.Remaining(), lot.BottleCount))
+			}
+			lot.LabelCount += count
+		}
+
+		data, err := protocol.EncodeRecord(lot)
+		if err != nil {
+			return &processor.InternalError{Msg: fmt.Sprint("Failed to encode lot: ", err)}
+		}
+		updates[address] = data
+	}
+	return nil
+}
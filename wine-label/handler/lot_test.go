@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"testing"
+
+	"wine-label-protocol/protocol"
+)
+
+func TestApplyCreateLot(t *testing.T) {
+	invalid := createLot("lot-2020", 10)
+	invalid.LotDetails.Varietals = nil
+	runCases(t, []handlerCase{
+		{name: "create", step: step{wineryKey, createLot("lot-2020", 10), ""},
+			check: func(t *testing.T, state *MemoryState) {
+				lot := getLot(t, state, "lot-2020")
+				if lot.Producer != "chateau" || lot.BottleCount != 10 || lot.LabelCount != 0 {
+					t.Errorf("create: lot = %+v", lot)
+				}
+				if len(state.Receipts) != 1 {
+					t.Errorf("create: %d receipts, want 1", len(state.Receipts))
+				}
+			}},
+		{name: "create by printer", step: step{printerKey, createLot("lot-2020", 10), ""}, invalid: true},
+		{name: "create by unregistered key", step: step{consumerKey, createLot("lot-2020", 10), ""}, invalid: true},
+		{name: "create twice", step: step{wineryKey, createLot(testLot, 10), ""}, invalid: true},
+		{name: "create invalid", step: step{wineryKey, invalid, ""}, invalid: true},
+	})
+}
+
+func TestApplyRecall(t *testing.T) {
+	recalled := []step{{wineryKey, recall(testLot, "cork taint"), ""}}
+	runCases(t, []handlerCase{
+		{name: "recall by producer", step: step{wineryKey, recall(testLot, "cork taint"), ""},
+			check: func(t *testing.T, state *MemoryState) {
+				lot := getLot(t, state, testLot)
+				if lot.Recall == nil || lot.Recall.Reason != "cork taint" || lot.Recall.RecalledBy != wineryKey {
+					t.Errorf("recall: lot = %+v", lot)
+				}
+				wantEvents(t, "recall", state, protocol.EVENT_LOT_RECALLED)
+				event := state.Events[0]
+				if event.Attribute(protocol.ATTRIBUTE_LOT_ID) != testLot || event.Attribute(protocol.ATTRIBUTE_REASON) != "cork taint" ||
+					event.Attribute(protocol.ATTRIBUTE_ADDRESS) != testNamespace.LotAddress(testLot) {
+					t.Errorf("recall: event attributes = %v", event.Attributes)
+				}
+			}},
+		{name: "recall by regulator", step: step{regulatorKey, recall(testLot, "cork taint"), ""}},
+		{name: "recall by printer", step: step{printerKey, recall(testLot, "cork taint"), ""}, invalid: true},
+		{name: "recall by unregistered key", step: step{consumerKey, recall(testLot, "cork taint"), ""}, invalid: true},
+		{name: "recall without reason", step: step{wineryKey, recall(testLot, ""), ""}, invalid: true},
+		{name: "recall twice", setup: recalled, step: step{regulatorKey, recall(testLot, "again"), ""}, invalid: true},
+		{name: "no such lot", step: step{regulatorKey, recall("lot-1999", "cork taint"), ""}, invalid: true},
+	})
+}
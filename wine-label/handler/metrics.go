@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/hyperledger/sawtooth-sdk-go/processor"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"wine-label-protocol/protocol"
+)
+
+// Outcomes of a transaction, and the reasons an invalid one is rejected
+// for. The handler names the reason where it rejects a transaction, see
+// reject.
+const (
+	OUTCOME_OK             string = "ok"
+	OUTCOME_INVALID        string = "invalid"
+	OUTCOME_INTERNAL_ERROR string = "internal_error"
+
+	REASON_MALFORMED    string = "malformed"
+	REASON_UNAUTHORIZED string = "unauthorized"
+	REASON_NOT_FOUND    string = "not_found"
+	REASON_CONFLICT     string = "conflict"
+	REASON_VALIDATION   string = "validation"
+
+	// Verbs no handler knows are counted as UNKNOWN_VERB, so that a
+	// sender cannot create metrics at will.
+	UNKNOWN_VERB string = "unknown"
+)
+
+var labelVerbs = []string{
+	protocol.VERB_SET, protocol.VERB_DELETE, protocol.VERB_OFFER, protocol.VERB_ACCEPT, protocol.VERB_CANCEL,
+	protocol.VERB_SCAN, protocol.VERB_CLAIM, protocol.VERB_MINT, protocol.VERB_CREATE_LOT, protocol.VERB_RECALL,
+	protocol.VERB_REGISTER_ORG, protocol.VERB_UPDATE_ORG,
+}
+
+// Metrics are the Prometheus metrics of the transactions a processor
+// applies, labelled by family so that tenants can be told apart. One
+// Metrics may be shared by the handlers of every tenant.
+type Metrics struct {
+	transactions *prometheus.CounterVec
+	latency      *prometheus.HistogramVec
+	state        *prometheus.CounterVec
+	inFlight     *prometheus.GaugeVec
+	maxQueueSize prometheus.Gauge
+}
+
+// NewMetrics creates the metrics and registers them with registerer.
+func NewMetrics(registerer prometheus.Registerer) (*Metrics, error) {
+	metrics := &Metrics{
+		transactions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "wine_label_transactions_total",
+			Help: "Transactions applied, by family, verb, outcome and, for invalid transactions, reason.",
+		}, []string{"family", "verb", "outcome", "reason"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "wine_label_apply_seconds",
+			Help:    "Time taken to apply a transaction, including state reads and writes.",
+			Buckets: prometheus.ExponentialBuckets(0.001, 2, 14),
+		}, []string{"family", "verb"}),
+		state: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "wine_label_state_addresses_total",
+			Help: "State addresses read, written and deleted, by family and operation.",
+		}, []string{"family", "operation"}),
+		// Queue depth is not available: the SDK keeps its work queue
+		// to itself. Transactions in flight stay at the worker thread
+		// count while the queue is backing up.
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "wine_label_transactions_in_flight",
+			Help: "Transactions being applied.",
+		}, []string{"family"}),
+		maxQueueSize: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "wine_label_max_queue_size",
+			Help: "Transactions the processor queues before rejecting requests.",
+		}),
+	}
+	collectors := []prometheus.Collector{
+		metrics.transactions, metrics.latency, metrics.state, metrics.inFlight, metrics.maxQueueSize,
+	}
+	for _, collector := range collectors {
+		if err := registerer.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+	return metrics, nil
+}
+
+// SetMaxQueueSize records the processor's queue size.
+func (self *Metrics) SetMaxQueueSize(size uint) {
+	self.maxQueueSize.Set(float64(size))
+}
+
+// begin counts a transaction in flight until the returned function is
+// called.
+func (self *Metrics) begin(familyName string) func() {
+	gauge := self.inFlight.WithLabelValues(familyName)
+	gauge.Inc()
+	return gauge.Dec
+}
+
+// observe records the outcome of a transaction and how long it took.
+func (self *Metrics) observe(familyName string, verb string, err error, elapsed time.Duration) {
+	verb = verbLabel(verb)
+	outcome, reason := Outcome(err)
+	self.transactions.WithLabelValues(familyName, verb, outcome, reason).Inc()
+	self.latency.WithLabelValues(familyName, verb).Observe(elapsed.Seconds())
+}
+
+// countState wraps context so that its state operations are counted.
+func (self *Metrics) countState(familyName string, context State) State {
+	return &countingState{
+		State:   context,
+		gets:    self.state.WithLabelValues(familyName, "get"),
+		sets:    self.state.WithLabelValues(familyName, "set"),
+		deletes: self.state.WithLabelValues(familyName, "delete"),
+	}
+}
+
+// rejection is the error of an invalid transaction, along with the reason
+// it was rejected for. ApplyState hands the validator the wrapped
+// InvalidTransactionError, the only form the SDK recognizes.
+type rejection struct {
+	*processor.InvalidTransactionError
+	reason string
+}
+
+// reject rejects a transaction as invalid for reason, one of the REASON
+// constants.
+func reject(reason string, msg string) error {
+	return &rejection{&processor.InvalidTransactionError{Msg: msg}, reason}
+}
+
+// Outcome classifies the error a transaction was applied with, returning
+// the reason only for invalid transactions. An InvalidTransactionError
+// that was not raised through reject counts as REASON_VALIDATION.
+func Outcome(err error) (string, string) {
+	switch err := err.(type) {
+	case nil:
+		return OUTCOME_OK, ""
+	case *rejection:
+		return OUTCOME_INVALID, err.reason
+	case *processor.InvalidTransactionError:
+		return OUTCOME_INVALID, REASON_VALIDATION
+	}
+	return OUTCOME_INTERNAL_ERROR, ""
+}
+
+func verbLabel(verb string) string {
+	if _, ok := protocol.StatusForVerb(verb); ok {
+		return verb
+	}
+	for _, known := range labelVerbs {
+		if verb == known {
+			return verb
+		}
+	}
+	return UNKNOWN_VERB
+}
+
+// countingState counts the addresses a transaction reads, writes and
+// deletes.
+type countingState struct {
+	State
+	gets    prometheus.Counter
+	sets    prometheus.Counter
+	deletes prometheus.Counter
+}
+
+func (self *countingState) GetState(addresses []string) (map[string][]byte, error) {
+	self.gets.Add(float64(len(addresses)))
+	return self.State.GetState(addresses)
+}
+
+func (self *countingState) SetState(pairs map[string][]byte) ([]string, error) {
+	self.sets.Add(float64(len(pairs)))
+	return self.State.SetState(pairs)
+}
+
+func (self *countingState) DeleteState(addresses []string) ([]string, error) {
+	self.deletes.Add(float64(len(addresses)))
+	return self.State.DeleteState(addresses)
+}
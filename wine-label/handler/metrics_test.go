@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/sawtooth-sdk-go/processor"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/processor_pb2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"wine-label-protocol/protocol"
+)
+
+func TestOutcome(t *testing.T) {
+	base := fixture(t)
+	mustApply(t, base, step{wineryKey, setLabel("125"), ""})
+	cases := []struct {
+		name   string
+		step   step
+		reason string
+	}{
+		{"unknown verb", step{wineryKey, labelVerb("relabel", "125"), ""}, REASON_MALFORMED},
+		{"not the owner", step{printerKey, labelVerb(protocol.VERB_APPLY, "125"), ""}, REASON_UNAUTHORIZED},
+		{"not an admin", step{wineryKey, registerOrg("shop", protocol.ORG_RETAILER, consumerKey), ""}, REASON_UNAUTHORIZED},
+		{"ID quoting another reason", step{wineryKey, registerOrg("already no such", protocol.ORG_RETAILER, consumerKey), ""}, REASON_UNAUTHORIZED},
+		{"no such label", step{wineryKey, labelVerb(protocol.VERB_APPLY, "126"), ""}, REASON_NOT_FOUND},
+		{"no such lot", step{regulatorKey, recall("lot-1999", "cork taint"), ""}, REASON_NOT_FOUND},
+		{"skipped status", step{wineryKey, labelVerb(protocol.VERB_SELL, "125"), ""}, REASON_CONFLICT},
+		{"registered key", step{adminKey, registerOrg("shop", protocol.ORG_RETAILER, wineryKey), ""}, REASON_CONFLICT},
+		{"invalid scan time", step{consumerKey, scan("125", testLongitude, testLatitude, "yesterday"), ""}, REASON_VALIDATION},
+	}
+	handler := NewWineLabelHandler(protocol.FAMILY_NAME, DefaultPolicy(protocol.FAMILY_NAME))
+	for _, c := range cases {
+		request, err := newRequest(handler, c.step)
+		if err != nil {
+			t.Fatal(err)
+		}
+		outcome, reason := Outcome(handler.apply(request, base.Copy()))
+		if outcome != OUTCOME_INVALID || reason != c.reason {
+			t.Errorf("%s: Outcome = %v, %v, want %v, %v", c.name, outcome, reason, OUTCOME_INVALID, c.reason)
+		}
+	}
+	if outcome, reason := Outcome(nil); outcome != OUTCOME_OK || reason != "" {
+		t.Errorf("Outcome(nil) = %v, %v", outcome, reason)
+	}
+	if outcome, _ := Outcome(&processor.InternalError{Msg: "no such"}); outcome != OUTCOME_INTERNAL_ERROR {
+		t.Errorf("Outcome(InternalError) = %v", outcome)
+	}
+	if outcome, _ := Outcome(errors.New("connection lost")); outcome != OUTCOME_INTERNAL_ERROR {
+		t.Errorf("Outcome(error) = %v", outcome)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	metrics, err := NewMetrics(registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := NewWineLabelHandler(protocol.FAMILY_NAME, DefaultPolicy(protocol.FAMILY_NAME))
+	handler.SetMetrics(metrics)
+	state := fixture(t)
+	for _, s := range []step{
+		{wineryKey, setLabel("125"), ""},
+		{printerKey, labelVerb(protocol.VERB_APPLY, "125"), ""},
+		{wineryKey, labelVerb("relabel", "125"), ""},
+	} {
+		applyWith(handler, state, s)
+	}
+	handler.ApplyState(&processor_pb2.TpProcessRequest{}, state)
+
+	family := protocol.FAMILY_NAME
+	counts := []struct {
+		labels []string
+		want   float64
+	}{
+		{[]string{family, protocol.VERB_SET, OUTCOME_OK, ""}, 1},
+		{[]string{family, protocol.VERB_APPLY, OUTCOME_INVALID, REASON_UNAUTHORIZED}, 1},
+		{[]string{family, UNKNOWN_VERB, OUTCOME_INVALID, REASON_MALFORMED}, 2},
+	}
+	for _, c := range counts {
+		if got := testutil.ToFloat64(metrics.transactions.WithLabelValues(c.labels...)); got != c.want {
+			t.Errorf("transactions%v = %v, want %v", c.labels, got, c.want)
+		}
+	}
+	// A new label is written with its history event and three index entries.
+	if got := testutil.ToFloat64(metrics.state.WithLabelValues(family, "set")); got != 5 {
+		t.Errorf("state sets = %v, want 5", got)
+	}
+	if got := testutil.ToFloat64(metrics.state.WithLabelValues(family, "get")); got == 0 {
+		t.Error("state gets were not counted")
+	}
+	if got := testutil.ToFloat64(metrics.inFlight.WithLabelValues(family)); got != 0 {
+		t.Errorf("transactions in flight = %v, want 0", got)
+	}
+	if got := testutil.CollectAndCount(metrics.latency); got != 3 {
+		t.Errorf("latency has %d series, want 3", got)
+	}
+}
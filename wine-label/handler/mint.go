@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/sawtooth-sdk-go/processor"
+	"github.com/sirupsen/logrus"
+
+	"wine-label-protocol/protocol"
+)
+
+// applyMint creates every label of a printed roll in one transaction. The
+// roll is rejected as a whole if any of its IDs is in use or was
+// withdrawn. Its print event is checked as applySet checks a label's, so
+// every label of the roll shares one validated print time.
+func (self *WineLabelHandler) applyMint(context State, familyVersion string, signer string, payload protocol.WineLabelPayload, log *logrus.Entry) error {
+	ids, err := payload.Mint.Labels()
+	if err != nil {
+		return reject(REASON_VALIDATION, fmt.Sprint("Cannot mint wine labels: ", err))
+	}
+	event := payload.Mint.Print
+	if event.IsSet() || familyVersion == protocol.FAMILY_VERSION_2 {
+		if err := event.Check(); err != nil {
+			return reject(REASON_VALIDATION, fmt.Sprint("Cannot mint wine labels: ", err))
+		}
+	}
+
+	updates := make(map[string][]byte)
+	txs := make([]*labelTransaction, 0, len(ids))
+	addresses := make([]string, 0, 3*len(ids))
+	for _, id := range ids {
+		if err := self.checkLabelID(payload.Verb, id); err != nil {
+			return err
+		}
+		tx := &labelTransaction{
+			context:       context,
+			familyVersion: familyVersion,
+			payload:       payload,
+			signer:        signer,
+			address:       self.namespace.LabelAddress(id),
+			updates:       updates,
+			log:           log.WithField("label_id", id),
+		}
+		tx.payload.Payload = payload.Mint.LabelPayload(id)
+		txs = append(txs, tx)
+		addresses = append(addresses, tx.address, self.namespace.LegacyLabelAddress(id), self.namespace.HistoryAddress(id, 0))
+	}
+	if err := self.requireLabelCreator(txs[0]); err != nil {
+		return err
+	}
+
+	results, err := context.GetState(addresses)
+	if err != nil {
+		return err
+	}
+	records := make([][]byte, 0, len(txs))
+	for _, tx := range txs {
+		id := tx.payload.WineLabelID
+		_, exists := results[tx.address]
+		if data, legacy := results[self.namespace.LegacyLabelAddress(id)]; legacy {
+			record, err := protocol.DecodeLabelRecord(data)
+			exists = exists || err != nil || record.WineLabelID != ""
+		}
+		if exists {
+			return reject(REASON_CONFLICT, fmt.Sprintf("Cannot mint wine label %v: label already exists", id))
+		}
+		if _, exists := results[self.namespace.HistoryAddress(id, 0)]; exists {
+			return reject(REASON_CONFLICT, fmt.Sprintf("Cannot mint wine label %v: label was withdrawn", id))
+		}
+		record, err := protocol.NewMintedRecord(payload.Mint, id, signer)
+		if err != nil {
+			return reject(REASON_VALIDATION, err.Error())
+		}
+		if err := self.checkPosition(payload.Verb, &record); err != nil {
+			return err
+		}
+		data, err := self.stage(tx, &record)
+		if err != nil {
+			return err
+		}
+		records = append(records, data)
+	}
+
+	if payload.Mint.Lot != "" {
+		deltas := map[string]uint64{payload.Mint.Lot: uint64(len(ids))}
+		if err := self.adjustLots(context, signer, deltas, nil, updates); err != nil {
+			return err
+		}
+	}
+
+	written, err := context.SetState(updates)
+	if err != nil {
+		return err
+	}
+	if len(written) != len(updates) {
+		return &processor.InternalError{Msg: "Missing addresses in set response"}
+	}
+	for i, tx := range txs {
+		if err := self.emit(tx, protocol.EVENT_CREATED, records[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"testing"
+
+	"wine-label-protocol/protocol"
+)
+
+func TestApplyMint(t *testing.T) {
+	withSecret := mint(1, 2, "")
+	withSecret.Mint.Secrets = map[string]protocol.LabelSecret{
+		"W-0002": {Salt: testSalt, Hash: protocol.SecretHash(testSalt, testSecret)},
+	}
+	legacy := mint(1, 2, "")
+	legacy.Mint.Print = protocol.PrintEvent{}
+	legacy.Mint.Printer = "press-1"
+	noOperator := mint(1, 2, "")
+	noOperator.Mint.Print.Operator = ""
+	runCases(t, []handlerCase{
+		{name: "mint", step: step{printerKey, mint(1, 2, testLot), ""},
+			check: func(t *testing.T, state *MemoryState) {
+				for _, id := range []string{"W-0001", "W-0002"} {
+					record := getLabel(t, state, id)
+					if record.Owner != printerKey || record.Printer != "press-1" || record.Operator != "alice" ||
+						record.PrintTime == "" || record.Lot != testLot || record.HistoryLength != 1 {
+						t.Errorf("mint: record = %+v", record)
+					}
+				}
+				if lot := getLot(t, state, testLot); lot.LabelCount != 2 {
+					t.Errorf("mint: lot has %d labels, want 2", lot.LabelCount)
+				}
+				wantEvents(t, "mint", state, protocol.EVENT_CREATED, protocol.EVENT_CREATED)
+			}},
+		{name: "mint with secrets", step: step{wineryKey, withSecret, ""},
+			check: func(t *testing.T, state *MemoryState) {
+				if getLabel(t, state, "W-0001").Secret.IsSet() || !getLabel(t, state, "W-0002").Secret.IsSet() {
+					t.Error("mint with secrets: secrets were not stored with their labels")
+				}
+			}},
+		{name: "mint without operator", step: step{printerKey, noOperator, ""}, invalid: true},
+		{name: "mint without print event", step: step{printerKey, legacy, ""}, invalid: true},
+		{name: "mint under 1.0 without print event", step: step{printerKey, legacy, protocol.FAMILY_VERSION_1},
+			check: func(t *testing.T, state *MemoryState) {
+				if record := getLabel(t, state, "W-0001"); record.Printer != "press-1" || record.PrintTime != "" {
+					t.Errorf("mint under 1.0: record = %+v", record)
+				}
+			}},
+		{name: "mint by unregistered key", step: step{consumerKey, mint(1, 2, ""), ""}, invalid: true},
+		{name: "mint by distributor", step: step{distributorKey, mint(1, 2, ""), ""}, invalid: true},
+		{name: "mint nothing", step: step{printerKey, mint(1, 0, ""), ""}, invalid: true},
+		{name: "mint more than the lot", step: step{printerKey, mint(1, testLotBottles+1, testLot), ""}, invalid: true},
+		{name: "mint existing label",
+			setup: []step{{printerKey, mint(2, 1, ""), ""}},
+			step:  step{printerKey, mint(1, 2, ""), ""}, invalid: true},
+		{name: "mint withdrawn label",
+			setup: []step{
+				{printerKey, mint(2, 1, ""), ""},
+				{printerKey, labelVerb(protocol.VERB_DELETE, "W-0002"), ""},
+			},
+			step: step{printerKey, mint(1, 2, ""), ""}, invalid: true},
+	})
+}
+
+// TestMintAddresses checks that a mint writes only to the outputs a client
+// declares for it, including the index entries of the roll's facility.
+func TestMintAddresses(t *testing.T) {
+	state := fixture(t)
+	before := state.Copy()
+	payload := mint(1, 2, testLot)
+	mustApply(t, state, step{printerKey, payload, ""})
+	_, outputs := protocol.TransactionAddresses(protocol.FAMILY_NAME, printerKey, payload)
+	for _, address := range changedAddresses(before, state) {
+		if !declared(address, outputs) {
+			t.Errorf("Mint wrote %v, which is not among its outputs", address)
+		}
+	}
+	facility := testNamespace.IndexAddress(protocol.FACILITY_INDEX_SPACE, payload.Mint.Print.Facility, "W-0001")
+	if !hasState(state, facility) {
+		t.Error("Mint did not index the labels by facility")
+	}
+}
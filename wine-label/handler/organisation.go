@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/sawtooth-sdk-go/processor"
+
+	"wine-label-protocol/protocol"
+)
+
+// applyOrganisation registers or updates an organisation and keeps the
+// key index, which maps each authorised key back to its organisation, in
+// step with the organisation's key list. Only family admins register
+// organisations; an organisation's own keys or an admin may update it.
+func (self *WineLabelHandler) applyOrganisation(context State, signer string, payload protocol.WineLabelPayload) error {
+	org, err := protocol.NewOrganisation(payload.Organisation)
+	if err != nil {
+		return reject(REASON_VALIDATION, err.Error())
+	}
+	address := self.namespace.OrganisationAddress(org.OrgID)
+	var existing protocol.Organisation
+	exists, err := getState(context, address, &existing)
+	if err != nil {
+		return err
+	}
+
+	admin, err := self.isAdmin(context, signer)
+	if err != nil {
+		return err
+	}
+
+	switch payload.Verb {
+	case protocol.VERB_REGISTER_ORG:
+		if exists {
+			return reject(REASON_CONFLICT, fmt.Sprintf("Organisation %v is already registered", org.OrgID))
+		}
+		if !admin {
+			return reject(REASON_UNAUTHORIZED,
+				fmt.Sprintf("Cannot register organisation %v: signer %v is not listed in %v",
+					org.OrgID, signer, self.policy.AdminsSetting))
+		}
+	case protocol.VERB_UPDATE_ORG:
+		if !exists {
+			return reject(REASON_NOT_FOUND,
+				fmt.Sprintf("Cannot update organisation %v: no such organisation", org.OrgID))
+		}
+		if !admin && !existing.HasKey(signer) {
+			return reject(REASON_UNAUTHORIZED,
+				fmt.Sprintf("Cannot update organisation %v: signer %v is neither an admin nor one of its keys",
+					org.OrgID, signer))
+		}
+		if org.Type != existing.Type {
+			return reject(REASON_CONFLICT,
+				fmt.Sprintf("Cannot update organisation %v: type cannot change from %v", org.OrgID, existing.Type))
+		}
+	}
+
+	data, err := protocol.EncodeRecord(org)
+	if err != nil {
+		return &processor.InternalError{Msg: fmt.Sprint("Failed to encode organisation: ", err)}
+	}
+	updates := map[string][]byte{address: data}
+	for _, key := range org.Keys {
+		if existing.HasKey(key) {
+			continue
+		}
+		registered, err := self.getKeyOrganisation(context, key)
+		if err != nil {
+			return err
+		}
+		if registered != "" {
+			return reject(REASON_CONFLICT,
+				fmt.Sprintf("Public key %v is already registered to organisation %v", key, registered))
+		}
+		data, err := protocol.EncodeRecord(protocol.KeyRecord{PublicKey: key, OrgID: org.OrgID})
+		if err != nil {
+			return &processor.InternalError{Msg: fmt.Sprint("Failed to encode key record: ", err)}
+		}
+		updates[self.namespace.KeyAddress(key)] = data
+	}
+	var removed []string
+	for _, key := range existing.Keys {
+		if !org.HasKey(key) {
+			removed = append(removed, self.namespace.KeyAddress(key))
+		}
+	}
+
+	addresses, err := context.SetState(updates)
+	if err != nil {
+		return err
+	}
+	if len(addresses) != len(updates) {
+		return &processor.InternalError{Msg: "Missing addresses in set response"}
+	}
+	if len(removed) > 0 {
+		addresses, err := context.DeleteState(removed)
+		if err != nil {
+			return err
+		}
+		if len(addresses) != len(removed) {
+			return &processor.InternalError{Msg: "Missing addresses in delete response"}
+		}
+	}
+	return context.AddReceiptData(data)
+}
+
+// getKeyOrganisation returns the ID of the organisation a public key is
+// registered to, or "" if it is not registered.
+func (self *WineLabelHandler) getKeyOrganisation(context State, key string) (string, error) {
+	var record protocol.KeyRecord
+	_, err := getState(context, self.namespace.KeyAddress(key), &record)
+	return record.OrgID, err
+}
+
+// getSignerOrganisation returns the organisation the signer's key is
+// registered to, or nil if it is not registered.
+func (self *WineLabelHandler) getSignerOrganisation(context State, signer string) (*protocol.Organisation, error) {
+	orgID, err := self.getKeyOrganisation(context, signer)
+	if orgID == "" || err != nil {
+		return nil, err
+	}
+	var org protocol.Organisation
+	exists, err := getState(context, self.namespace.OrganisationAddress(orgID), &org)
+	if !exists || err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
+
+// requireLabelCreator rejects label creation unless the signer is
+// registered to a winery or a licensed printer.
+func (self *WineLabelHandler) requireLabelCreator(tx *labelTransaction) error {
+	org, err := self.getSignerOrganisation(tx.context, tx.signer)
+	if err != nil {
+		return err
+	}
+	if org == nil || !protocol.CanCreateLabels(org.Type) {
+		return reject(REASON_UNAUTHORIZED,
+			fmt.Sprintf("Cannot %v wine label %v: signer %v is not registered to a winery or printer",
+				tx.payload.Verb, tx.payload.WineLabelID, tx.signer))
+	}
+	return nil
+}
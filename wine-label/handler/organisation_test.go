@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"reflect"
+	"testing"
+
+	"wine-label-protocol/protocol"
+)
+
+func TestApplyOrganisation(t *testing.T) {
+	runCases(t, []handlerCase{
+		{name: "register", step: step{adminKey, registerOrg("shop", protocol.ORG_RETAILER, consumerKey, otherKey), ""},
+			check: func(t *testing.T, state *MemoryState) {
+				org := getOrganisation(t, state, "shop")
+				if org.Type != protocol.ORG_RETAILER || !reflect.DeepEqual(org.Keys, []string{consumerKey, otherKey}) {
+					t.Errorf("register: organisation = %+v", org)
+				}
+				for _, key := range org.Keys {
+					var record protocol.KeyRecord
+					if _, err := getState(state, testNamespace.KeyAddress(key), &record); err != nil || record.OrgID != "shop" {
+						t.Errorf("register: key %v maps to %+v, %v", key, record, err)
+					}
+				}
+				if len(state.Receipts) != 1 {
+					t.Errorf("register: %d receipts, want 1", len(state.Receipts))
+				}
+			}},
+		{name: "register by non-admin", step: step{wineryKey, registerOrg("shop", protocol.ORG_RETAILER, consumerKey), ""}, invalid: true},
+		{name: "register twice", step: step{adminKey, registerOrg("chateau", protocol.ORG_WINERY, consumerKey), ""}, invalid: true},
+		{name: "register registered key", step: step{adminKey, registerOrg("shop", protocol.ORG_RETAILER, wineryKey), ""}, invalid: true},
+		{name: "register invalid key", step: step{adminKey, registerOrg("shop", protocol.ORG_RETAILER, "02ab"), ""}, invalid: true},
+		{name: "register invalid type", step: step{adminKey, registerOrg("shop", "brewery", consumerKey), ""}, invalid: true},
+		{name: "update by own key", step: step{wineryKey, updateOrg("chateau", protocol.ORG_WINERY, otherKey), ""},
+			check: func(t *testing.T, state *MemoryState) {
+				if org := getOrganisation(t, state, "chateau"); !reflect.DeepEqual(org.Keys, []string{otherKey}) {
+					t.Errorf("update: organisation = %+v", org)
+				}
+				if hasState(state, testNamespace.KeyAddress(wineryKey)) {
+					t.Error("update: removed key is still registered")
+				}
+				if !hasState(state, testNamespace.KeyAddress(otherKey)) {
+					t.Error("update: added key is not registered")
+				}
+			}},
+		{name: "update by admin", step: step{adminKey, updateOrg("chateau", protocol.ORG_WINERY, wineryKey, otherKey), ""}},
+		{name: "update by another organisation", step: step{printerKey, updateOrg("chateau", protocol.ORG_WINERY, printerKey), ""}, invalid: true},
+		{name: "update type", step: step{wineryKey, updateOrg("chateau", protocol.ORG_RETAILER, wineryKey), ""}, invalid: true},
+		{name: "update unregistered", step: step{adminKey, updateOrg("shop", protocol.ORG_RETAILER, consumerKey), ""}, invalid: true},
+	})
+}
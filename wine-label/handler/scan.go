@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"fmt"
+
+	"wine-label-protocol/protocol"
+)
+
+// applyScan records a scan of a label by anyone, usually a consumer
+// checking a bottle, and flags the label as suspicious if the scan
+// suggests it has been cloned. Scans of labels in a recalled lot are
+// announced as well. The payload's ClaimedAt is the scan time.
+func (self *WineLabelHandler) applyScan(tx *labelTransaction) error {
+	if err := self.requireLabel(tx); err != nil {
+		return err
+	}
+	position, err := protocol.ParseGeoPoint(tx.payload.Lattitude, tx.payload.Longitude)
+	if err != nil {
+		return reject(REASON_VALIDATION,
+			fmt.Sprintf("Cannot scan wine label %v: %v", tx.payload.WineLabelID, err))
+	}
+	record := *tx.label
+	record.ScanRegions = append([]string(nil), tx.label.ScanRegions...)
+	reason, err := record.RecordScan(protocol.Scan{
+		Scanner:   tx.signer,
+		Position:  position,
+		ScannedAt: tx.payload.ClaimedAt,
+	})
+	if err != nil {
+		return reject(REASON_VALIDATION,
+			fmt.Sprintf("Cannot scan wine label %v: %v", tx.payload.WineLabelID, err))
+	}
+	var lot protocol.Lot
+	if record.Lot != "" {
+		if _, err := getState(tx.context, self.namespace.LotAddress(record.Lot), &lot); err != nil {
+			return err
+		}
+	}
+	if err := self.commit(tx, &record); err != nil {
+		return err
+	}
+	if reason != "" {
+		tx.log.WithField("reason", reason).Warn("Wine label is suspicious")
+		if err := self.emitAlert(tx, protocol.EVENT_SUSPICIOUS, record, reason); err != nil {
+			return err
+		}
+	}
+	if lot.Recall != nil {
+		return self.emitAlert(tx, protocol.EVENT_RECALLED_SCAN, record, lot.Recall.Reason)
+	}
+	return nil
+}
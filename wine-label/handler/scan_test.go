@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"testing"
+
+	"wine-label-protocol/protocol"
+)
+
+func TestApplyScan(t *testing.T) {
+	created := []step{{wineryKey, setLabelInLot("125", testLot), ""}}
+	scanned := append(created, step{consumerKey, scan("125", testLongitude, testLatitude, "2020-01-02T03:04:05Z"), ""})
+	recalled := append(created, step{wineryKey, recall(testLot, "cork taint"), ""})
+	runCases(t, []handlerCase{
+		{name: "scan", setup: created, step: step{consumerKey, scan("125", testLongitude, testLatitude, "2020-01-02T03:04:05Z"), ""},
+			check: func(t *testing.T, state *MemoryState) {
+				record := getLabel(t, state, "125")
+				if record.ScanCount != 1 || record.LastScan.Scanner != consumerKey || record.Suspicious {
+					t.Errorf("scan: record = %+v", record)
+				}
+				wantEvents(t, "scan", state, protocol.EVENT_SCANNED)
+			}},
+		{name: "impossible travel", setup: scanned, step: step{otherKey, scan("125", farLongitude, farLatitude, "2020-01-02T04:04:05Z"), ""},
+			check: func(t *testing.T, state *MemoryState) {
+				if record := getLabel(t, state, "125"); !record.Suspicious || record.SuspiciousReason == "" {
+					t.Errorf("impossible travel: record = %+v", record)
+				}
+				wantEvents(t, "impossible travel", state, protocol.EVENT_SCANNED, protocol.EVENT_SUSPICIOUS)
+				wantAlert(t, "impossible travel", state, protocol.EVENT_SUSPICIOUS, "")
+			}},
+		{name: "recalled lot", setup: recalled, step: step{consumerKey, scan("125", testLongitude, testLatitude, "2020-01-02T03:04:05Z"), ""},
+			check: func(t *testing.T, state *MemoryState) {
+				wantEvents(t, "recalled lot", state, protocol.EVENT_SCANNED, protocol.EVENT_RECALLED_SCAN)
+				wantAlert(t, "recalled lot", state, protocol.EVENT_RECALLED_SCAN, "cork taint")
+			}},
+		{name: "before the last scan", setup: scanned, step: step{otherKey, scan("125", farLongitude, farLatitude, "2020-01-02T02:04:05Z"), ""}, invalid: true},
+		{name: "invalid time", setup: created, step: step{consumerKey, scan("125", testLongitude, testLatitude, "yesterday"), ""}, invalid: true},
+		{name: "invalid position", setup: created, step: step{consumerKey, scan("125", "181", testLatitude, "2020-01-02T03:04:05Z"), ""}, invalid: true},
+		{name: "no such label", step: step{consumerKey, scan("125", testLongitude, testLatitude, "2020-01-02T03:04:05Z"), ""}, invalid: true},
+	})
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/sawtooth-sdk-go/processor"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/setting_pb2"
+
+	"wine-label-protocol/protocol"
+)
+
+// getSetting reads an on-chain setting written by the settings transaction
+// family, returning "" if it is not set.
+func getSetting(context State, key string) (string, error) {
+	address := protocol.SettingAddress(key)
+	results, err := context.GetState([]string{address})
+	if err != nil {
+		return "", err
+	}
+	data, exists := results[address]
+	if !exists {
+		return "", nil
+	}
+	setting := &setting_pb2.Setting{}
+	err = proto.Unmarshal(data, setting)
+	if err != nil {
+		return "", &processor.InternalError{
+			Msg: fmt.Sprintf("Failed to decode setting %v: %v", key, err),
+		}
+	}
+	// More than one entry means another key hashed to the same address.
+	for _, entry := range setting.GetEntries() {
+		if entry.GetKey() == key {
+			return entry.GetValue(), nil
+		}
+	}
+	return "", nil
+}
+
+// isAdmin reports whether signer is listed in the tenant's admins setting.
+func (self *WineLabelHandler) isAdmin(context State, signer string) (bool, error) {
+	return isListed(context, self.policy.AdminsSetting, signer)
+}
+
+// isRegulator reports whether signer is listed in the tenant's regulators
+// setting.
+func (self *WineLabelHandler) isRegulator(context State, signer string) (bool, error) {
+	return isListed(context, self.policy.RegulatorsSetting, signer)
+}
+
+// isListed reports whether signer is one of the keys in a setting.
+func isListed(context State, key string, signer string) (bool, error) {
+	value, err := getSetting(context, key)
+	if err != nil {
+		return false, err
+	}
+	for _, key := range protocol.ParseKeyList(value) {
+		if key == signer {
+			return true, nil
+		}
+	}
+	return false, nil
+}
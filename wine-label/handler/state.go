@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/sawtooth-sdk-go/processor"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
+
+	"wine-label-protocol/protocol"
+)
+
+// State is the part of a validator's context the handler uses. The SDK's
+// processor.Context implements it, and so does MemoryState.
+type State interface {
+	GetState(addresses []string) (map[string][]byte, error)
+	SetState(pairs map[string][]byte) ([]string, error)
+	DeleteState(addresses []string) ([]string, error)
+	AddEvent(eventType string, attributes []processor.Attribute, data []byte) error
+	AddReceiptData(data []byte) error
+}
+
+// Event is an event added to a MemoryState.
+type Event struct {
+	Type       string
+	Attributes []processor.Attribute
+	Data       []byte
+}
+
+// Attribute returns the value of the event's attribute with the given key,
+// or "" if it has none.
+func (self Event) Attribute(key string) string {
+	for _, attribute := range self.Attributes {
+		if attribute.Key == key {
+			return attribute.Value
+		}
+	}
+	return ""
+}
+
+// MemoryState is a State held in memory, for running the handler without
+// a validator. Unlike a validator it keeps the writes of a transaction
+// that fails, so each transaction that is expected to fail should be
+// applied to a Copy.
+type MemoryState struct {
+	entries  map[string][]byte
+	Events   []Event
+	Receipts [][]byte
+}
+
+func NewMemoryState() *MemoryState {
+	return &MemoryState{entries: make(map[string][]byte)}
+}
+
+// Copy returns a MemoryState with the same entries and no events or
+// receipts.
+func (self *MemoryState) Copy() *MemoryState {
+	result := NewMemoryState()
+	for address, data := range self.entries {
+		result.entries[address] = data
+	}
+	return result
+}
+
+// Addresses returns every address that holds data, in order.
+func (self *MemoryState) Addresses() []string {
+	addresses := make([]string, 0, len(self.entries))
+	for address := range self.entries {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+	return addresses
+}
+
+// Context returns a State for applying the transaction with the given
+// header to the MemoryState. Like a validator's context, it refuses to read
+// addresses outside the header's inputs or to write or delete addresses
+// outside its outputs, either of which may list address prefixes.
+func (self *MemoryState) Context(header *transaction_pb2.TransactionHeader) State {
+	return &memoryContext{MemoryState: self, inputs: header.Inputs, outputs: header.Outputs}
+}
+
+func (self *MemoryState) GetState(addresses []string) (map[string][]byte, error) {
+	if err := checkAddresses(addresses); err != nil {
+		return nil, err
+	}
+	results := make(map[string][]byte)
+	for _, address := range addresses {
+		if data, exists := self.entries[address]; exists {
+			results[address] = append([]byte(nil), data...)
+		}
+	}
+	return results, nil
+}
+
+func (self *MemoryState) SetState(pairs map[string][]byte) ([]string, error) {
+	addresses := make([]string, 0, len(pairs))
+	for address := range pairs {
+		addresses = append(addresses, address)
+	}
+	if err := checkAddresses(addresses); err != nil {
+		return nil, err
+	}
+	sort.Strings(addresses)
+	for _, address := range addresses {
+		self.entries[address] = append([]byte(nil), pairs[address]...)
+	}
+	return addresses, nil
+}
+
+// DeleteState deletes the addresses that hold data and returns them.
+func (self *MemoryState) DeleteState(addresses []string) ([]string, error) {
+	if err := checkAddresses(addresses); err != nil {
+		return nil, err
+	}
+	deleted := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		if _, exists := self.entries[address]; exists {
+			delete(self.entries, address)
+			deleted = append(deleted, address)
+		}
+	}
+	return deleted, nil
+}
+
+func (self *MemoryState) AddEvent(eventType string, attributes []processor.Attribute, data []byte) error {
+	self.Events = append(self.Events, Event{Type: eventType, Attributes: attributes, Data: data})
+	return nil
+}
+
+func (self *MemoryState) AddReceiptData(data []byte) error {
+	self.Receipts = append(self.Receipts, data)
+	return nil
+}
+
+type memoryContext struct {
+	*MemoryState
+	inputs  []string
+	outputs []string
+}
+
+func (self *memoryContext) GetState(addresses []string) (map[string][]byte, error) {
+	if err := authorize("get", addresses, self.inputs); err != nil {
+		return nil, err
+	}
+	return self.MemoryState.GetState(addresses)
+}
+
+func (self *memoryContext) SetState(pairs map[string][]byte) ([]string, error) {
+	addresses := make([]string, 0, len(pairs))
+	for address := range pairs {
+		addresses = append(addresses, address)
+	}
+	if err := authorize("set", addresses, self.outputs); err != nil {
+		return nil, err
+	}
+	return self.MemoryState.SetState(pairs)
+}
+
+func (self *memoryContext) DeleteState(addresses []string) ([]string, error) {
+	if err := authorize("delete", addresses, self.outputs); err != nil {
+		return nil, err
+	}
+	return self.MemoryState.DeleteState(addresses)
+}
+
+// authorize fails as a validator does when any of addresses is not under
+// one of the declared addresses.
+func authorize(action string, addresses []string, declared []string) error {
+	var unauthorized []string
+	for _, address := range addresses {
+		found := false
+		for _, prefix := range declared {
+			found = found || strings.HasPrefix(address, prefix)
+		}
+		if !found {
+			unauthorized = append(unauthorized, address)
+		}
+	}
+	if len(unauthorized) > 0 {
+		sort.Strings(unauthorized)
+		return &processor.AuthorizationException{
+			Msg: fmt.Sprintf("Tried to %v unauthorized address: %v", action, unauthorized)}
+	}
+	return nil
+}
+
+// checkAddresses rejects addresses a validator would not accept, which
+// can only come from a bug in the handler.
+func checkAddresses(addresses []string) error {
+	for _, address := range addresses {
+		valid := len(address) == protocol.ADDRESS_LENGTH
+		for _, r := range address {
+			valid = valid && (r >= '0' && r <= '9' || r >= 'a' && r <= 'f')
+		}
+		if !valid {
+			return &processor.InternalError{Msg: fmt.Sprintf("Invalid address %q", address)}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hyperledger/sawtooth-sdk-go/processor"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
+)
+
+func TestMemoryState(t *testing.T) {
+	state := NewMemoryState()
+	first := testNamespace.LabelAddress("1")
+	second := testNamespace.LabelAddress("2")
+	if _, err := state.SetState(map[string][]byte{first: []byte("a"), second: []byte("b")}); err != nil {
+		t.Fatal(err)
+	}
+	copied := state.Copy()
+	deleted, err := state.DeleteState([]string{first, testNamespace.LabelAddress("3")})
+	if err != nil || !reflect.DeepEqual(deleted, []string{first}) {
+		t.Errorf("DeleteState = %v, %v, want only the stored address", deleted, err)
+	}
+	results, err := state.GetState([]string{first, second})
+	if err != nil || !reflect.DeepEqual(results, map[string][]byte{second: []byte("b")}) {
+		t.Errorf("GetState = %v, %v", results, err)
+	}
+	if !hasState(copied, first) {
+		t.Error("DeleteState changed a copy")
+	}
+	for _, address := range []string{"", "b25576", first[:69] + "G"} {
+		if _, err := state.GetState([]string{address}); err == nil {
+			t.Errorf("GetState accepted address %q", address)
+		}
+	}
+	state.AddEvent("wine-label/created", []processor.Attribute{{Key: "label_id", Value: "2"}}, nil)
+	if len(state.Events) != 1 || state.Events[0].Attribute("label_id") != "2" {
+		t.Errorf("Events = %+v", state.Events)
+	}
+}
+
+func TestMemoryStateContext(t *testing.T) {
+	state := NewMemoryState()
+	label := testNamespace.LabelAddress("1")
+	history := testNamespace.HistoryAddress("1", 0)
+	context := state.Context(&transaction_pb2.TransactionHeader{
+		Inputs:  []string{label, testNamespace.HistoryPrefix("1")},
+		Outputs: []string{label},
+	})
+	if _, err := context.GetState([]string{label, history}); err != nil {
+		t.Errorf("GetState of declared inputs: %v", err)
+	}
+	if _, err := context.SetState(map[string][]byte{label: []byte("a")}); err != nil {
+		t.Errorf("SetState of a declared output: %v", err)
+	}
+	other := testNamespace.LabelAddress("2")
+	for name, err := range map[string]error{
+		"get":    errorOf(context.GetState([]string{label, other})),
+		"set":    errorOf(context.SetState(map[string][]byte{history: []byte("b")})),
+		"delete": errorOf(context.DeleteState([]string{other})),
+	} {
+		if _, ok := err.(*processor.AuthorizationException); !ok {
+			t.Errorf("%s outside the header = %v, want an authorization error", name, err)
+		}
+	}
+	if hasState(state, history) || !hasState(state, label) {
+		t.Errorf("Context wrote %v", state.Addresses())
+	}
+}
+
+func errorOf(_ interface{}, err error) error {
+	return err
+}
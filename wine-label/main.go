@@ -2,35 +2,46 @@ package main
 
 import (
 	"fmt"
+	"net/http"
 	"os"
 	"syscall"
 
 	"github.com/hyperledger/sawtooth-sdk-go/logging"
 	"github.com/hyperledger/sawtooth-sdk-go/processor"
 	flags "github.com/jessevdk/go-flags"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
 
+	"wine-label/config"
 	intkey "wine-label/handler"
 )
 
 type Opts struct {
-	Verbose []bool `short:"v" long:"verbose" description:"Increase verbosity"`
-	Connect string `short:"C" long:"connect" description:"Validator component endpoint to connect to" default:"tcp://localhost:4004"`
-	Queue   uint   `long:"max-queue-size" description:"Set the maximum queue size before rejecting process requests" default:"100"`
-	Threads uint   `long:"worker-thread-count" description:"Set the number of worker threads to use for processing requests in parallel" default:"0"`
+	Verbose        []bool   `short:"v" long:"verbose" description:"Increase verbosity"`
+	Config         string   `long:"config" description:"Read the configuration from this YAML file; also WINE_LABEL_CONFIG"`
+	Connect        string   `short:"C" long:"connect" description:"Validator component endpoint to connect to (default: tcp://localhost:4004)"`
+	Queue          uint     `long:"max-queue-size" description:"Set the maximum queue size before rejecting process requests (default: 100)"`
+	Threads        uint     `long:"worker-thread-count" description:"Set the number of worker threads to use for processing requests in parallel"`
+	PayloadSize    int      `long:"max-payload-size" description:"Reject transaction payloads larger than this many bytes (default: 65536)"`
+	LabelIDPattern string   `long:"label-id-pattern" description:"Require the IDs of new labels to match this regular expression"`
+	Bounds         string   `long:"bounds" description:"Require new labels to be printed within SOUTH,WEST,NORTH,EAST"`
+	Tenants        []string `long:"tenant" description:"Serve a consortium's tenant; may be repeated (default: default)"`
+	Metrics        string   `long:"metrics-listen" description:"Serve Prometheus metrics at /metrics on this address, e.g. :9100"`
+	Level          string   `long:"log-level" description:"Log level; overrides -v" choice:"debug" choice:"info" choice:"warn" choice:"error"`
+	Format         string   `long:"log-format" description:"Log format (default: logfmt)" choice:"logfmt" choice:"json"`
 }
 
 func main() {
 	var opts Opts
 
-	logger := logging.Get()
-
 	parser := flags.NewParser(&opts, flags.Default)
 	remaining, err := parser.Parse()
 	if err != nil {
 		if flagsErr, ok := err.(*flags.Error); ok && flagsErr.Type == flags.ErrHelp {
 			os.Exit(0)
 		} else {
-			logger.Errorf("Failed to parse args: %v", err)
+			logrus.WithError(err).Error("Failed to parse args")
 			os.Exit(2)
 		}
 	}
@@ -40,30 +51,184 @@ func main() {
 		os.Exit(2)
 	}
 
-	endpoint := opts.Connect
-
-	switch len(opts.Verbose) {
-	case 2:
-		logger.SetLevel(logging.DEBUG)
-	case 1:
-		logger.SetLevel(logging.INFO)
-	default:
-		logger.SetLevel(logging.WARN)
+	var handlers []*intkey.WineLabelHandler
+	cfg, path, err := loadConfig(parser, &opts)
+	if err == nil {
+		err = cfg.Validate()
+	}
+	if err == nil {
+		handlers, err = cfg.Handlers()
+	}
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(2)
 	}
 
-	prefix := intkey.Hexdigest("wine-label")[:6]
-	fmt.Println("Prefix :", prefix)
-	handler := intkey.NewWineLabelHandler(prefix)
-	processor := processor.NewTransactionProcessor(endpoint)
-	processor.SetMaxQueueSize(opts.Queue)
-	if opts.Threads > 0 {
-		processor.SetThreadCount(opts.Threads)
+	configureLogging(cfg.LogLevel, cfg.LogFormat, len(opts.Verbose))
+	logrus.WithFields(logrus.Fields{
+		"config":              path,
+		"connect":             cfg.Connect,
+		"max_queue_size":      cfg.MaxQueueSize,
+		"worker_thread_count": cfg.Threads,
+		"metrics_listen":      cfg.MetricsListen,
+		"log_level":           logrus.GetLevel().String(),
+		"log_format":          cfg.LogFormat,
+	}).Info("Effective configuration")
+
+	if cfg.MetricsListen != "" {
+		metrics, err := serveMetrics(cfg.MetricsListen)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to set up metrics")
+			os.Exit(1)
+		}
+		metrics.SetMaxQueueSize(cfg.MaxQueueSize)
+		for _, handler := range handlers {
+			handler.SetMetrics(metrics)
+		}
+	}
+	processor := processor.NewTransactionProcessor(cfg.Connect)
+	processor.SetMaxQueueSize(cfg.MaxQueueSize)
+	if cfg.Threads > 0 {
+		processor.SetThreadCount(cfg.Threads)
+	}
+	for _, handler := range handlers {
+		logrus.WithFields(policyFields(handler.Policy())).WithFields(logrus.Fields{
+			"family":      handler.FamilyName(),
+			"prefix":      handler.Namespaces()[0],
+			"policy_hash": handler.Policy().Hash(),
+		}).Info("Serving family")
+		processor.AddHandler(handler)
 	}
-	processor.AddHandler(handler)
 	processor.ShutdownOnSignal(syscall.SIGINT, syscall.SIGTERM)
 	err = processor.Start()
 	if err != nil {
-		logger.Error("Processor stopped: ", err)
+		logrus.WithError(err).Error("Processor stopped")
+	}
+
+}
+
+// loadConfig merges the defaults, the config file, the environment and
+// the flags given on the command line, each over the one before, and
+// returns the result with the path of the file read, if any.
+func loadConfig(parser *flags.Parser, opts *Opts) (*config.Config, string, error) {
+	cfg := config.Default()
+	path := opts.Config
+	if path == "" {
+		path = os.Getenv(config.ENV_PREFIX + "CONFIG")
+	}
+	if path != "" {
+		if err := cfg.Load(path); err != nil {
+			return nil, path, err
+		}
+	}
+	if err := cfg.LoadEnv(os.LookupEnv); err != nil {
+		return nil, path, err
+	}
+
+	isSet := func(name string) bool {
+		return parser.FindOptionByLongName(name).IsSet()
+	}
+	if isSet("connect") {
+		cfg.Connect = opts.Connect
+	}
+	if isSet("max-queue-size") {
+		cfg.MaxQueueSize = opts.Queue
+	}
+	if isSet("worker-thread-count") {
+		cfg.Threads = opts.Threads
+	}
+	if isSet("max-payload-size") {
+		cfg.Policy.MaxPayloadSize = opts.PayloadSize
+	}
+	if isSet("label-id-pattern") {
+		cfg.Policy.LabelIDPattern = opts.LabelIDPattern
+	}
+	if isSet("bounds") {
+		cfg.Policy.Bounds = opts.Bounds
+	}
+	if isSet("metrics-listen") {
+		cfg.MetricsListen = opts.Metrics
+	}
+	if isSet("log-level") {
+		cfg.LogLevel = opts.Level
+	}
+	if isSet("log-format") {
+		cfg.LogFormat = opts.Format
+	}
+	if isSet("tenant") {
+		cfg.SetTenants(opts.Tenants)
+	}
+	return cfg, path, nil
+}
+
+// policyFields describes the policy a handler enforces, after defaults and
+// inheritance, for the log.
+func policyFields(policy intkey.Policy) logrus.Fields {
+	fields := logrus.Fields{
+		"max_payload_size": policy.MaxPayloadSize,
+		"label_id_pattern": "",
+		"bounds":           "",
+	}
+	if policy.LabelIDPattern != nil {
+		fields["label_id_pattern"] = policy.LabelIDPattern.String()
+	}
+	if policy.Bounds != nil {
+		fields["bounds"] = fmt.Sprintf("%v to %v", policy.Bounds.Min, policy.Bounds.Max)
+	}
+	return fields
+}
+
+// serveMetrics registers the handler metrics, along with the Go runtime
+// and process metrics, and serves them on address in the background.
+func serveMetrics(address string) (*intkey.Metrics, error) {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(prometheus.NewGoCollector())
+	registry.MustRegister(prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}))
+	metrics, err := intkey.NewMetrics(registry)
+	if err != nil {
+		return nil, err
 	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	go func() {
+		err := http.ListenAndServe(address, mux)
+		logrus.WithError(err).WithField("address", address).Error("Metrics listener stopped")
+	}()
+	return metrics, nil
+}
 
+// configureLogging sets the level and format of the processor's logs. The
+// level is warn, info with -v or debug with -vv, unless given.
+// The SDK logs through its own logger, which gets the same level.
+func configureLogging(level string, format string, verbosity int) {
+	levels := map[string]logrus.Level{
+		"debug": logrus.DebugLevel,
+		"info":  logrus.InfoLevel,
+		"warn":  logrus.WarnLevel,
+		"error": logrus.ErrorLevel,
+	}
+	if level == "" {
+		switch {
+		case verbosity >= 2:
+			level = "debug"
+		case verbosity == 1:
+			level = "info"
+		default:
+			level = "warn"
+		}
+	}
+	logrus.SetLevel(levels[level])
+	sdkLevels := map[string]int{
+		"debug": logging.DEBUG,
+		"info":  logging.INFO,
+		"warn":  logging.WARN,
+		"error": logging.ERROR,
+	}
+	logging.Get().SetLevel(sdkLevels[level])
+
+	if format == "json" {
+		logrus.SetFormatter(&logrus.JSONFormatter{})
+	} else {
+		logrus.SetFormatter(&logrus.TextFormatter{DisableColors: true, FullTimestamp: true})
+	}
 }